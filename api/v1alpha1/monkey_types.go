@@ -17,11 +17,39 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
+// KillMode defines how many matching pods are deleted by each Chaos experiment
+// +kubebuilder:validation:Enum=one;fixed;percent;all
+type KillMode string
+
+const (
+	// KillModeOne deletes a single pod
+	KillModeOne KillMode = "one"
+	// KillModeFixed deletes value pods
+	KillModeFixed KillMode = "fixed"
+	// KillModePercent deletes value percent of the matching pods
+	KillModePercent KillMode = "percent"
+	// KillModeAll deletes every matching pod
+	KillModeAll KillMode = "all"
+)
+
+// DefaultInterval is the interval between Chaos experiments when none is set
+const DefaultInterval = 30 * time.Second
+
+// DefaultGracePeriodSeconds is the grace period pods are deleted with when none is set, killing them immediately
+const DefaultGracePeriodSeconds int64 = 0
+
+// UsePodGracePeriod is the gracePeriodSeconds value that uses the terminationGracePeriodSeconds of each pod
+const UsePodGracePeriod int64 = -1
+
 // MonkeySpec defines the desired state of Monkey
 type MonkeySpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
@@ -31,19 +59,417 @@ type MonkeySpec struct {
 	// +optional
 	Noop bool `json:"noop,omitempty"`
 
+	// suspend stops Chaos experiments from running until it is unset, the schedule restarts from the time the monkey is resumed
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
 	// interval defines interval to requeue Chaos experiment to kill a random pod with matching selector
 	// +optional
 	Interval string `json:"interval,omitempty"`
 
+	// maxInterval defines the maximum interval between Chaos experiments, each interval is chosen at random between interval and maxInterval
+	// +optional
+	MaxInterval string `json:"maxInterval,omitempty"`
+
+	// schedule defines a cron expression for when Chaos experiments run, taking precedence over interval
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// timeZone defines the IANA time zone the schedule is evaluated in, defaults to UTC
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
 	// Namespace defines namespace to search for pods to delete
 	Namespace string `json:"namespace,omitempty"`
 
+	// namespaces defines additional namespaces to search for pods to delete
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// namespaceSelector selects namespaces by label to search for pods to delete
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
 	Selector metav1.LabelSelector `json:"selector,omitempty"`
+
+	// excludeSelector excludes the pods it matches from those matching the selector
+	// +optional
+	ExcludeSelector *metav1.LabelSelector `json:"excludeSelector,omitempty"`
+
+	// candidates defines which of the pods matching the selector may be deleted, defaults to ready, running pods that are
+	// not already being deleted
+	// +optional
+	Candidates *CandidatePolicy `json:"candidates,omitempty"`
+
+	// mode defines how many matching pods are deleted by each experiment, defaults to one
+	// +optional
+	Mode KillMode `json:"mode,omitempty"`
+
+	// value defines the number of pods to delete in fixed mode or the percentage of matching pods to delete in percent mode
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Value int32 `json:"value,omitempty"`
+
+	// useEviction defines whether to evict pods through the Eviction API, honoring PodDisruptionBudgets, instead of deleting them
+	// +optional
+	UseEviction bool `json:"useEviction,omitempty"`
+
+	// gracePeriodSeconds defines the grace period given to pods when they are deleted, defaults to 0 to kill pods immediately.
+	// Set to -1 to use the terminationGracePeriodSeconds of each pod.
+	// +optional
+	// +kubebuilder:validation:Minimum=-1
+	GracePeriodSeconds *int64 `json:"gracePeriodSeconds,omitempty"`
+
+	// recoveryTimeout enables checking that the workload owning each deleted pod returns to its desired number of
+	// ready replicas, failing the check if it has not recovered within the timeout
+	// +optional
+	RecoveryTimeout string `json:"recoveryTimeout,omitempty"`
+
+	// blackoutWindows defines periods of time during which no pods are deleted
+	// +optional
+	BlackoutWindows []BlackoutWindow `json:"blackoutWindows,omitempty"`
+
+	// duration defines how long the monkey runs Chaos experiments for, measured from its startTime, before it completes
+	// +optional
+	Duration string `json:"duration,omitempty"`
+
+	// maxKills defines how many pods the monkey deletes before it completes
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxKills int64 `json:"maxKills,omitempty"`
+
+	// abortPolicy defines when the controller halts the Monkey until it is resumed with the resume annotation
+	// +optional
+	AbortPolicy *AbortPolicy `json:"abortPolicy,omitempty"`
 }
 
+// AbortPolicy defines the workload health thresholds that halt a Monkey
+type AbortPolicy struct {
+	// maxUnreadyPods halts the Monkey when more pods matching the selector than this are not ready
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxUnreadyPods *int32 `json:"maxUnreadyPods,omitempty"`
+
+	// maxConsecutiveRecoveryFailures halts the Monkey when this many recovery checks fail in a row, requires recoveryTimeout
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxConsecutiveRecoveryFailures int32 `json:"maxConsecutiveRecoveryFailures,omitempty"`
+}
+
+// CandidatePolicy defines which of the pods matching the selector of a Monkey may be deleted
+type CandidatePolicy struct {
+	// phases lists the phases of the pods that may be deleted, defaults to Running
+	// +optional
+	Phases []corev1.PodPhase `json:"phases,omitempty"`
+
+	// includeUnready allows pods that are not ready to be deleted
+	// +optional
+	IncludeUnready bool `json:"includeUnready,omitempty"`
+
+	// includeTerminating allows pods that are already being deleted to be deleted again
+	// +optional
+	IncludeTerminating bool `json:"includeTerminating,omitempty"`
+}
+
+// ResumeAnnotation is the annotation that resumes a halted Monkey, it is removed by the controller once the Monkey is resumed
+const ResumeAnnotation = "podchaosmonkey.pt/resume"
+
+// ExcludeAnnotation is the pod annotation that excludes a pod from every Monkey when set to "true"
+const ExcludeAnnotation = "podchaosmonkey.pt/exclude"
+
+// BlackoutWindow defines a period of time during which Chaos experiments are suspended.
+// Either start and end for an absolute window or schedule and duration for a recurring window must be set.
+type BlackoutWindow struct {
+	// name describes the blackout window, e.g. "christmas-freeze"
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// start defines when an absolute blackout window begins
+	// +optional
+	Start *metav1.Time `json:"start,omitempty"`
+
+	// end defines when an absolute blackout window ends
+	// +optional
+	End *metav1.Time `json:"end,omitempty"`
+
+	// schedule defines a cron expression for when a recurring blackout window begins, evaluated in the Monkey's timeZone
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// duration defines how long a recurring blackout window lasts
+	// +optional
+	Duration string `json:"duration,omitempty"`
+}
+
+const (
+	// ConditionReady is true when the monkey's spec is valid and the controller is running it as specified
+	ConditionReady = "Ready"
+
+	// ReasonReconciled is used when the monkey is ready
+	ReasonReconciled = "Reconciled"
+
+	// ConditionActive is true when the monkey is due to run further experiments
+	ConditionActive = "Active"
+
+	// ReasonRunning is used when the monkey is active
+	ReasonRunning = "Running"
+
+	// ConditionDegraded is true when the monkey is halted, refused, or has evictions blocked
+	ConditionDegraded = "Degraded"
+
+	// ReasonAsExpected is used when the monkey is not degraded
+	ReasonAsExpected = "AsExpected"
+
+	// ConditionInvalidSpec is true when the monkey's spec cannot be run
+	ConditionInvalidSpec = "InvalidSpec"
+
+	// ReasonValid is used when the monkey's spec is valid
+	ReasonValid = "Valid"
+
+	// ReasonInvalidInterval is used when the interval or maxInterval of the monkey cannot be parsed or is not positive
+	ReasonInvalidInterval = "InvalidInterval"
+
+	// ReasonInvalidSchedule is used when the schedule, timeZone or blackout windows of the monkey are not valid
+	ReasonInvalidSchedule = "InvalidSchedule"
+
+	// ReasonInvalidSelector is used when the selector, excludeSelector or namespaceSelector of the monkey cannot be converted,
+	// or its candidates policy lists an unknown phase
+	ReasonInvalidSelector = "InvalidSelector"
+
+	// ReasonInvalidMode is used when the mode of the monkey is unknown or its value is not valid for its mode
+	ReasonInvalidMode = "InvalidMode"
+
+	// ReasonInvalidDuration is used when the recoveryTimeout or duration of the monkey cannot be parsed
+	ReasonInvalidDuration = "InvalidDuration"
+
+	// ConditionRefused is set when the controller refuses to run an experiment
+	ConditionRefused = "Refused"
+
+	// ReasonProtectedNamespace is used when a monkey targets a namespace protected by the controller
+	ReasonProtectedNamespace = "ProtectedNamespace"
+
+	// ReasonNamespaceOutOfScope is used when a monkey targets namespaces the controller does not watch
+	ReasonNamespaceOutOfScope = "NamespaceOutOfScope"
+
+	// ReasonAllowed is used when the controller no longer refuses the experiments of the monkey
+	ReasonAllowed = "Allowed"
+
+	// ConditionSuspended is set when Chaos experiments are suspended
+	ConditionSuspended = "Suspended"
+
+	// ReasonBlackoutWindow is used when experiments are suspended by a blackout window
+	ReasonBlackoutWindow = "BlackoutWindow"
+
+	// ReasonKillSwitch is used when experiments are suspended by the controller's kill switch
+	ReasonKillSwitch = "KillSwitch"
+
+	// ReasonSuspendedBySpec is used when experiments are suspended by the suspend field of the Monkey
+	ReasonSuspendedBySpec = "SuspendedBySpec"
+
+	// ReasonActive is used when experiments are no longer suspended
+	ReasonActive = "Active"
+
+	// ConditionEvictionBlocked is set when evicting a pod was blocked
+	ConditionEvictionBlocked = "EvictionBlocked"
+
+	// ReasonPodDisruptionBudget is used when an eviction was blocked by a PodDisruptionBudget
+	ReasonPodDisruptionBudget = "PodDisruptionBudget"
+
+	// ReasonEvicted is used when the last eviction was no longer blocked
+	ReasonEvicted = "Evicted"
+
+	// ConditionTargetUnhealthy is set when an experiment was skipped because the targeted workloads were not in a steady state
+	ConditionTargetUnhealthy = "TargetUnhealthy"
+
+	// ReasonWorkloadUnhealthy is used when a targeted workload has unready replicas or pods in CrashLoopBackOff
+	ReasonWorkloadUnhealthy = "WorkloadUnhealthy"
+
+	// ReasonHealthy is used when the targeted workloads are back in a steady state
+	ReasonHealthy = "Healthy"
+
+	// ConditionHalted is set when the abort policy stopped the Monkey
+	ConditionHalted = "Halted"
+
+	// ReasonUnreadyPods is used when more pods matching the selector are unready than the abort policy allows
+	ReasonUnreadyPods = "UnreadyPods"
+
+	// ReasonRecoveryFailures is used when more recovery checks failed in a row than the abort policy allows
+	ReasonRecoveryFailures = "RecoveryFailures"
+
+	// ReasonResumed is used when a halted monkey was resumed with the resume annotation
+	ReasonResumed = "Resumed"
+
+	// ConditionCompleted is set when the monkey reached its duration or maxKills and no longer runs experiments
+	ConditionCompleted = "Completed"
+
+	// ReasonDurationElapsed is used when the duration of the monkey has elapsed
+	ReasonDurationElapsed = "DurationElapsed"
+
+	// ReasonMaxKillsReached is used when the monkey has deleted maxKills pods
+	ReasonMaxKillsReached = "MaxKillsReached"
+)
+
 // MonkeyStatus defines the observed state of Monkey
 type MonkeyStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// startTime is when the monkey was registered, the duration of the monkey is measured from it
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// nextExperimentTime is when the next Chaos experiment is planned to run
+	// +optional
+	NextExperimentTime *metav1.Time `json:"nextExperimentTime,omitempty"`
+
+	// lastExperimentTime is when the most recent Chaos experiment ran, including experiments that were skipped or refused
+	// +optional
+	LastExperimentTime *metav1.Time `json:"lastExperimentTime,omitempty"`
+
+	// observedGeneration is the generation of the spec the next experiment was planned from
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// lastVictims lists the pods deleted by the most recent experiment that deleted pods
+	// +optional
+	LastVictims []Victim `json:"lastVictims,omitempty"`
+
+	// history lists the most recent actions taken by Chaos experiments, oldest first
+	// +optional
+	History []ExperimentRecord `json:"history,omitempty"`
+
+	// totalKills is the number of pods deleted by Chaos experiments
+	// +optional
+	TotalKills int64 `json:"totalKills,omitempty"`
+
+	// lastKillTime is when a Chaos experiment last deleted a pod
+	// +optional
+	LastKillTime *metav1.Time `json:"lastKillTime,omitempty"`
+
+	// pendingRecoveries lists the workloads being checked for recovery after a pod was deleted
+	// +optional
+	PendingRecoveries []RecoveryCheck `json:"pendingRecoveries,omitempty"`
+
+	// lastRecovery is the most recently completed recovery check
+	// +optional
+	LastRecovery *RecoveryCheck `json:"lastRecovery,omitempty"`
+
+	// consecutiveRecoveryFailures is the number of recovery checks that failed since the last one passed
+	// +optional
+	ConsecutiveRecoveryFailures int32 `json:"consecutiveRecoveryFailures,omitempty"`
+}
+
+// WorkloadReference identifies the controller owning a pod
+type WorkloadReference struct {
+	APIVersion string    `json:"apiVersion"`
+	Kind       string    `json:"kind"`
+	Name       string    `json:"name"`
+	UID        types.UID `json:"uid,omitempty"`
+}
+
+// RecoveryResult defines the outcome of a recovery check
+type RecoveryResult string
+
+const (
+	// RecoveryPassed is used when the workload recovered within the recovery timeout
+	RecoveryPassed RecoveryResult = "Passed"
+	// RecoveryFailed is used when the workload did not recover within the recovery timeout
+	RecoveryFailed RecoveryResult = "Failed"
+)
+
+// RecoveryCheck tracks whether the workload owning a deleted pod recovers
+type RecoveryCheck struct {
+	Namespace string            `json:"namespace"`
+	Owner     WorkloadReference `json:"owner"`
+
+	// podName is the name of the deleted pod
+	PodName string `json:"podName"`
+
+	// podUID is the UID of the deleted pod
+	// +optional
+	PodUID types.UID `json:"podUID,omitempty"`
+
+	// startTime is when the pod was deleted
+	StartTime metav1.Time `json:"startTime"`
+
+	// deadline is when the check fails if the workload has not recovered
+	Deadline metav1.Time `json:"deadline"`
+
+	// result is set once the check has completed
+	// +optional
+	Result RecoveryResult `json:"result,omitempty"`
+
+	// timeToRecovery is how long the workload took to recover
+	// +optional
+	TimeToRecovery *metav1.Duration `json:"timeToRecovery,omitempty"`
+}
+
+// ExperimentAction defines the action a Chaos experiment takes against a pod
+type ExperimentAction string
+
+const (
+	// ActionDelete deletes the pod
+	ActionDelete ExperimentAction = "Delete"
+	// ActionEvict evicts the pod through the Eviction API
+	ActionEvict ExperimentAction = "Evict"
+)
+
+// ExperimentResult defines the outcome of an action taken by a Chaos experiment
+type ExperimentResult string
+
+const (
+	// ResultSucceeded is used when the pod was deleted
+	ResultSucceeded ExperimentResult = "Succeeded"
+	// ResultSkipped is used when the pod was not deleted, either because of noop or because it no longer existed
+	ResultSkipped ExperimentResult = "Skipped"
+	// ResultBlocked is used when the eviction of the pod was blocked by a PodDisruptionBudget
+	ResultBlocked ExperimentResult = "Blocked"
+	// ResultFailed is used when deleting the pod failed
+	ResultFailed ExperimentResult = "Failed"
+)
+
+// ExperimentRecord records an action taken by a Chaos experiment against a pod
+type ExperimentRecord struct {
+	// time is when the action was taken
+	Time metav1.Time `json:"time"`
+
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	UID       types.UID `json:"uid,omitempty"`
+
+	// node is the node the pod was running on
+	// +optional
+	Node string `json:"node,omitempty"`
+
+	// owner is the kind and name of the controller owning the pod, e.g. ReplicaSet/nginx-66b6c48dd5
+	// +optional
+	Owner string `json:"owner,omitempty"`
+
+	Action ExperimentAction `json:"action"`
+
+	// gracePeriodSeconds is the grace period the pod was deleted with
+	// +optional
+	GracePeriodSeconds *int64 `json:"gracePeriodSeconds,omitempty"`
+
+	// noop is set when the action was only logged
+	// +optional
+	Noop bool `json:"noop,omitempty"`
+
+	Result ExperimentResult `json:"result"`
+
+	// message describes why the action was skipped, blocked or failed
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// Victim identifies a pod deleted by a Chaos experiment
+type Victim struct {
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	UID       types.UID `json:"uid,omitempty"`
+
+	// gracePeriodSeconds is the grace period the pod was deleted with
+	// +optional
+	GracePeriodSeconds *int64 `json:"gracePeriodSeconds,omitempty"`
 }
 
 //+kubebuilder:object:root=true
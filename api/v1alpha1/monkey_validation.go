@@ -0,0 +1,179 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ParseSchedule parses a standard cron expression evaluated in the time zone provided
+func ParseSchedule(schedule, timeZone string) (cron.Schedule, error) {
+	if timeZone != "" {
+		if _, err := time.LoadLocation(timeZone); err != nil {
+			return nil, err
+		}
+		schedule = fmt.Sprintf("CRON_TZ=%s %s", timeZone, schedule)
+	}
+	return cron.ParseStandard(schedule)
+}
+
+// ParseInterval parses the interval of a Monkey, which defaults to DefaultInterval when empty
+func ParseInterval(interval string) (time.Duration, error) {
+	if interval == "" {
+		return DefaultInterval, nil
+	}
+	return time.ParseDuration(interval)
+}
+
+// ValidateMonkeySpec checks that the controller can run a Monkey spec. It is shared by the validating webhook and the
+// controller, which also validates Monkeys admitted while the webhook was disabled, so both accept the same specs
+func ValidateMonkeySpec(spec *MonkeySpec, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	interval, intervalErr := ParseInterval(spec.Interval)
+	if intervalErr != nil {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("interval"), spec.Interval, intervalErr.Error()))
+	} else if interval <= 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("interval"), spec.Interval, "must be positive"))
+	}
+	if spec.MaxInterval != "" && intervalErr == nil {
+		maxInterval, err := time.ParseDuration(spec.MaxInterval)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("maxInterval"), spec.MaxInterval, err.Error()))
+		} else if maxInterval < interval {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("maxInterval"), spec.MaxInterval,
+				fmt.Sprintf("must not be less than interval %s", interval)))
+		}
+	}
+
+	// An invalid time zone is reported once, schedules are then checked in UTC
+	timeZone := spec.TimeZone
+	if timeZone != "" {
+		if _, err := time.LoadLocation(timeZone); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("timeZone"), spec.TimeZone, err.Error()))
+			timeZone = ""
+		}
+	}
+	if spec.Schedule != "" {
+		if _, err := ParseSchedule(spec.Schedule, timeZone); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("schedule"), spec.Schedule, err.Error()))
+		}
+	}
+	for i, window := range spec.BlackoutWindows {
+		allErrs = append(allErrs, validateBlackoutWindow(specPath.Child("blackoutWindows").Index(i), window, timeZone)...)
+	}
+
+	for _, duration := range []struct {
+		path  *field.Path
+		value string
+	}{
+		{specPath.Child("recoveryTimeout"), spec.RecoveryTimeout},
+		{specPath.Child("duration"), spec.Duration},
+	} {
+		if duration.value == "" {
+			continue
+		}
+		if parsed, err := time.ParseDuration(duration.value); err != nil {
+			allErrs = append(allErrs, field.Invalid(duration.path, duration.value, err.Error()))
+		} else if parsed <= 0 {
+			allErrs = append(allErrs, field.Invalid(duration.path, duration.value, "must be positive"))
+		}
+	}
+
+	if _, err := metav1.LabelSelectorAsSelector(&spec.Selector); err != nil {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("selector"), spec.Selector, err.Error()))
+	}
+	if spec.ExcludeSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(spec.ExcludeSelector); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("excludeSelector"), spec.ExcludeSelector, err.Error()))
+		}
+	}
+	if spec.NamespaceSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(spec.NamespaceSelector); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("namespaceSelector"), spec.NamespaceSelector, err.Error()))
+		}
+	}
+	if spec.Candidates != nil {
+		phases := []string{string(corev1.PodPending), string(corev1.PodRunning), string(corev1.PodSucceeded), string(corev1.PodFailed), string(corev1.PodUnknown)}
+		for i, phase := range spec.Candidates.Phases {
+			switch phase {
+			case corev1.PodPending, corev1.PodRunning, corev1.PodSucceeded, corev1.PodFailed, corev1.PodUnknown:
+			default:
+				allErrs = append(allErrs, field.NotSupported(specPath.Child("candidates", "phases").Index(i), phase, phases))
+			}
+		}
+	}
+
+	switch spec.Mode {
+	case "", KillModeOne, KillModeAll:
+	case KillModeFixed:
+		if spec.Value < 1 {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("value"), spec.Value,
+				fmt.Sprintf("must be at least 1 when mode is %s", spec.Mode)))
+		}
+	case KillModePercent:
+		if spec.Value < 1 || spec.Value > 100 {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("value"), spec.Value,
+				fmt.Sprintf("must be between 1 and 100 when mode is %s", spec.Mode)))
+		}
+	default:
+		modes := []string{string(KillModeOne), string(KillModeFixed), string(KillModePercent), string(KillModeAll)}
+		allErrs = append(allErrs, field.NotSupported(specPath.Child("mode"), spec.Mode, modes))
+	}
+	return allErrs
+}
+
+// validateBlackoutWindow checks that a blackout window is either an absolute window with a start before its end, or a
+// recurring window with a schedule evaluated in the time zone provided and a positive duration
+func validateBlackoutWindow(path *field.Path, window BlackoutWindow, timeZone string) field.ErrorList {
+	var allErrs field.ErrorList
+	if window.Schedule == "" {
+		if window.Start == nil {
+			allErrs = append(allErrs, field.Required(path.Child("start"), "start and end or schedule and duration are required"))
+		}
+		if window.End == nil {
+			allErrs = append(allErrs, field.Required(path.Child("end"), "start and end or schedule and duration are required"))
+		}
+		if window.Duration != "" {
+			allErrs = append(allErrs, field.Forbidden(path.Child("duration"), "duration requires a schedule"))
+		}
+		if window.Start != nil && window.End != nil && !window.Start.Before(window.End) {
+			allErrs = append(allErrs, field.Invalid(path.Child("end"), window.End, "must be after start"))
+		}
+		return allErrs
+	}
+	if window.Start != nil || window.End != nil {
+		allErrs = append(allErrs, field.Forbidden(path, "start and end cannot be set with a schedule"))
+	}
+	if _, err := ParseSchedule(window.Schedule, timeZone); err != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("schedule"), window.Schedule, err.Error()))
+	}
+	if window.Duration == "" {
+		allErrs = append(allErrs, field.Required(path.Child("duration"), "a schedule requires a duration"))
+	} else if duration, err := time.ParseDuration(window.Duration); err != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("duration"), window.Duration, err.Error()))
+	} else if duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("duration"), window.Duration, "must be positive"))
+	}
+	return allErrs
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var monkeylog = logf.Log.WithName("monkey-resource")
+
+// MonkeyValidator validates Monkeys against the configuration of the controller
+// +kubebuilder:object:generate=false
+type MonkeyValidator struct {
+	// MinInterval is the shortest interval allowed between Chaos experiments
+	MinInterval time.Duration
+
+	// ProtectedNamespaces cannot be targeted by a Monkey
+	ProtectedNamespaces []string
+}
+
+// SetupWebhookWithManager registers the defaulting and validating Monkey webhooks with the manager
+func (r *Monkey) SetupWebhookWithManager(mgr ctrl.Manager, validator *MonkeyValidator) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithValidator(validator).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-podchaos-podchaosmonkey-pt-v1alpha1-monkey,mutating=true,failurePolicy=fail,sideEffects=None,groups=podchaos.podchaosmonkey.pt,resources=monkeys,verbs=create;update,versions=v1alpha1,name=mmonkey.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &Monkey{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type, it materializes the defaults the
+// controller would otherwise apply so the effective configuration is visible on the Monkey
+func (r *Monkey) Default() {
+	monkeylog.Info("default", "name", r.Name)
+
+	if r.Spec.Interval == "" && r.Spec.Schedule == "" {
+		r.Spec.Interval = DefaultInterval.String()
+	}
+	if r.Spec.Mode == "" {
+		r.Spec.Mode = KillModeOne
+	}
+	if r.Spec.GracePeriodSeconds == nil {
+		gracePeriodSeconds := DefaultGracePeriodSeconds
+		r.Spec.GracePeriodSeconds = &gracePeriodSeconds
+	}
+	// A monkey without any namespaces targets its own namespace
+	if r.Spec.Namespace == "" && len(r.Spec.Namespaces) == 0 && r.Spec.NamespaceSelector == nil {
+		r.Spec.Namespace = r.Namespace
+	}
+}
+
+//+kubebuilder:webhook:path=/validate-podchaos-podchaosmonkey-pt-v1alpha1-monkey,mutating=false,failurePolicy=fail,sideEffects=None,groups=podchaos.podchaosmonkey.pt,resources=monkeys,verbs=create;update,versions=v1alpha1,name=vmonkey.kb.io,admissionReviewVersions=v1
+
+var _ admission.CustomValidator = &MonkeyValidator{}
+
+// ValidateCreate implements admission.CustomValidator so a webhook will be registered for the type
+func (v *MonkeyValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	monkey, ok := obj.(*Monkey)
+	if !ok {
+		return fmt.Errorf("expected a Monkey but got a %T", obj)
+	}
+	monkeylog.Info("validate create", "name", monkey.Name)
+	return v.validateMonkey(monkey)
+}
+
+// ValidateUpdate implements admission.CustomValidator so a webhook will be registered for the type
+func (v *MonkeyValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	monkey, ok := newObj.(*Monkey)
+	if !ok {
+		return fmt.Errorf("expected a Monkey but got a %T", newObj)
+	}
+	monkeylog.Info("validate update", "name", monkey.Name)
+	return v.validateMonkey(monkey)
+}
+
+// ValidateDelete implements admission.CustomValidator so a webhook will be registered for the type
+func (v *MonkeyValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+func (v *MonkeyValidator) validateMonkey(monkey *Monkey) error {
+	allErrs := v.validateMonkeySpec(monkey)
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: GroupVersion.Group, Kind: "Monkey"},
+		monkey.Name, allErrs)
+}
+
+// validateMonkeySpec checks that the controller can run a Monkey with ValidateMonkeySpec, then applies the admission
+// policy of the controller: a minimum interval between experiments, a non-empty selector and protected namespaces
+func (v *MonkeyValidator) validateMonkeySpec(monkey *Monkey) field.ErrorList {
+	spec := &monkey.Spec
+	specPath := field.NewPath("spec")
+	allErrs := ValidateMonkeySpec(spec, specPath)
+
+	if spec.Schedule == "" {
+		// The interval is not used when a schedule is set
+		if interval, err := ParseInterval(spec.Interval); err == nil && interval > 0 && interval < v.MinInterval {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("interval"), spec.Interval,
+				fmt.Sprintf("must be at least %s", v.MinInterval)))
+		}
+	} else if schedule, err := ParseSchedule(spec.Schedule, spec.TimeZone); err == nil {
+		if gap := minScheduleGap(schedule, time.Now()); gap < v.MinInterval {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("schedule"), spec.Schedule,
+				fmt.Sprintf("runs %s apart, must be at least %s apart", gap, v.MinInterval)))
+		}
+	}
+	if len(spec.Selector.MatchLabels) == 0 && len(spec.Selector.MatchExpressions) == 0 {
+		allErrs = append(allErrs, field.Required(specPath.Child("selector"), "an empty selector would match every pod"))
+	}
+
+	allErrs = append(allErrs, v.validateNamespace(specPath.Child("namespace"), spec.Namespace)...)
+	for i, namespace := range spec.Namespaces {
+		allErrs = append(allErrs, v.validateNamespace(specPath.Child("namespaces").Index(i), namespace)...)
+	}
+	// A monkey without any namespaces targets its own namespace
+	if spec.Namespace == "" && len(spec.Namespaces) == 0 && spec.NamespaceSelector == nil {
+		allErrs = append(allErrs, v.validateNamespace(field.NewPath("metadata", "namespace"), monkey.Namespace)...)
+	}
+	return allErrs
+}
+
+// scheduleGapSamples is how many consecutive runs of a schedule are compared to find the shortest gap between them
+const scheduleGapSamples = 100
+
+// minScheduleGap returns the shortest gap between consecutive runs of a schedule after the time provided
+func minScheduleGap(schedule cron.Schedule, after time.Time) time.Duration {
+	min := time.Duration(math.MaxInt64)
+	previous := schedule.Next(after)
+	for i := 0; i < scheduleGapSamples && !previous.IsZero(); i++ {
+		next := schedule.Next(previous)
+		if next.IsZero() {
+			break
+		}
+		if gap := next.Sub(previous); gap < min {
+			min = gap
+		}
+		previous = next
+	}
+	return min
+}
+
+func (v *MonkeyValidator) validateNamespace(path *field.Path, namespace string) field.ErrorList {
+	for _, protected := range v.ProtectedNamespaces {
+		if namespace != "" && namespace == protected {
+			return field.ErrorList{field.Forbidden(path, fmt.Sprintf("namespace %s is protected by the controller", namespace))}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,288 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMonkeyValidator_ValidateCreate(t *testing.T) {
+	validator := &MonkeyValidator{
+		MinInterval:         time.Minute,
+		ProtectedNamespaces: []string{"kube-system", "podchaosmonkey-system"},
+	}
+	selector := metav1.LabelSelector{MatchLabels: map[string]string{"chaosAllowed": "true"}}
+	christmas := metav1.NewTime(time.Date(2022, time.December, 25, 0, 0, 0, 0, time.UTC))
+	boxingDay := metav1.NewTime(christmas.Add(24 * time.Hour))
+	tests := []struct {
+		name        string
+		namespace   string
+		minInterval time.Duration
+		spec        MonkeySpec
+		wantFields  []string
+	}{
+		{
+			name:      "valid",
+			namespace: "workloads",
+			spec:      MonkeySpec{Interval: "5m", MaxInterval: "10m", Selector: selector},
+		},
+		{
+			name:      "schedule ignores the default interval",
+			namespace: "workloads",
+			spec:      MonkeySpec{Schedule: "0 9 * * 1-5", TimeZone: "Europe/Lisbon", Selector: selector},
+		},
+		{
+			name:       "unparseable interval",
+			namespace:  "workloads",
+			spec:       MonkeySpec{Interval: "often", Selector: selector},
+			wantFields: []string{"spec.interval"},
+		},
+		{
+			name:       "interval below the floor",
+			namespace:  "workloads",
+			spec:       MonkeySpec{Interval: "10s", Selector: selector},
+			wantFields: []string{"spec.interval"},
+		},
+		{
+			name:       "default interval below the floor",
+			namespace:  "workloads",
+			spec:       MonkeySpec{Selector: selector},
+			wantFields: []string{"spec.interval"},
+		},
+		{
+			name:       "zero interval",
+			namespace:  "workloads",
+			spec:       MonkeySpec{Interval: "0s", Selector: selector},
+			wantFields: []string{"spec.interval"},
+		},
+		{
+			name:       "maxInterval below interval",
+			namespace:  "workloads",
+			spec:       MonkeySpec{Interval: "5m", MaxInterval: "1m", Selector: selector},
+			wantFields: []string{"spec.maxInterval"},
+		},
+		{
+			name:       "invalid schedule and time zone",
+			namespace:  "workloads",
+			spec:       MonkeySpec{Schedule: "every monday", TimeZone: "Mars/Olympus_Mons", Selector: selector},
+			wantFields: []string{"spec.timeZone", "spec.schedule"},
+		},
+		{
+			name:       "schedule below the floor",
+			namespace:  "workloads",
+			spec:       MonkeySpec{Schedule: "@every 1s", Selector: selector},
+			wantFields: []string{"spec.schedule"},
+		},
+		{
+			name:        "per minute schedule below the floor",
+			namespace:   "workloads",
+			minInterval: 5 * time.Minute,
+			spec:        MonkeySpec{Schedule: "* * * * *", TimeZone: "Europe/Lisbon", Selector: selector},
+			wantFields:  []string{"spec.schedule"},
+		},
+		{
+			name:        "schedule at the floor",
+			namespace:   "workloads",
+			minInterval: 5 * time.Minute,
+			spec:        MonkeySpec{Schedule: "*/5 * * * *", Selector: selector},
+		},
+		{
+			name:        "schedule with a short gap",
+			namespace:   "workloads",
+			minInterval: 5 * time.Minute,
+			spec:        MonkeySpec{Schedule: "0,1 9 * * *", Selector: selector},
+			wantFields:  []string{"spec.schedule"},
+		},
+		{
+			name:      "valid blackout windows",
+			namespace: "workloads",
+			spec: MonkeySpec{Interval: "5m", Selector: selector, BlackoutWindows: []BlackoutWindow{
+				{Name: "christmas", Start: &christmas, End: &boxingDay},
+				{Name: "nights", Schedule: "0 22 * * *", Duration: "8h"},
+			}},
+		},
+		{
+			name:      "invalid blackout windows",
+			namespace: "workloads",
+			spec: MonkeySpec{Interval: "5m", Selector: selector, BlackoutWindows: []BlackoutWindow{
+				{Name: "backwards", Start: &boxingDay, End: &christmas},
+				{Name: "open ended", Start: &christmas},
+				{Name: "nights", Schedule: "at night", Duration: "0s"},
+			}},
+			wantFields: []string{
+				"spec.blackoutWindows[0].end",
+				"spec.blackoutWindows[1].end",
+				"spec.blackoutWindows[2].schedule",
+				"spec.blackoutWindows[2].duration",
+			},
+		},
+		{
+			name:       "unknown mode",
+			namespace:  "workloads",
+			spec:       MonkeySpec{Interval: "5m", Selector: selector, Mode: "some"},
+			wantFields: []string{"spec.mode"},
+		},
+		{
+			name:       "percent out of range",
+			namespace:  "workloads",
+			spec:       MonkeySpec{Interval: "5m", Selector: selector, Mode: KillModePercent, Value: 150},
+			wantFields: []string{"spec.value"},
+		},
+		{
+			name:       "fixed without a value",
+			namespace:  "workloads",
+			spec:       MonkeySpec{Interval: "5m", Selector: selector, Mode: KillModeFixed},
+			wantFields: []string{"spec.value"},
+		},
+		{
+			name:       "invalid durations",
+			namespace:  "workloads",
+			spec:       MonkeySpec{Interval: "5m", RecoveryTimeout: "soon", Duration: "-1h", Selector: selector},
+			wantFields: []string{"spec.recoveryTimeout", "spec.duration"},
+		},
+		{
+			name:       "empty selector",
+			namespace:  "workloads",
+			spec:       MonkeySpec{Interval: "5m"},
+			wantFields: []string{"spec.selector"},
+		},
+		{
+			name:      "invalid exclude selector",
+			namespace: "workloads",
+			spec: MonkeySpec{Interval: "5m", Selector: selector,
+				ExcludeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"role/": "leader"}}},
+			wantFields: []string{"spec.excludeSelector"},
+		},
+		{
+			name:      "candidate phases",
+			namespace: "workloads",
+			spec: MonkeySpec{Interval: "5m", Selector: selector,
+				Candidates: &CandidatePolicy{Phases: []corev1.PodPhase{corev1.PodRunning, "Crashing"}}},
+			wantFields: []string{"spec.candidates.phases[1]"},
+		},
+		{
+			name:      "protected namespaces",
+			namespace: "workloads",
+			spec: MonkeySpec{Interval: "5m", Selector: selector, Namespace: "kube-system",
+				Namespaces: []string{"workloads", "podchaosmonkey-system"}},
+			wantFields: []string{"spec.namespace", "spec.namespaces[1]"},
+		},
+		{
+			name:       "protected own namespace",
+			namespace:  "kube-system",
+			spec:       MonkeySpec{Interval: "5m", Selector: selector},
+			wantFields: []string{"metadata.namespace"},
+		},
+		{
+			name:      "own namespace is not targeted",
+			namespace: "kube-system",
+			spec:      MonkeySpec{Interval: "5m", Selector: selector, Namespace: "workloads"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			monkey := &Monkey{
+				ObjectMeta: metav1.ObjectMeta{Name: "monkey", Namespace: tt.namespace},
+				Spec:       tt.spec,
+			}
+			v := *validator
+			if tt.minInterval != 0 {
+				v.MinInterval = tt.minInterval
+			}
+			err := v.ValidateCreate(context.Background(), monkey)
+			if len(tt.wantFields) == 0 {
+				g.Expect(err).ToNot(HaveOccurred())
+				return
+			}
+			g.Expect(apierrors.IsInvalid(err)).Should(BeTrue())
+			var fields []string
+			for _, cause := range err.(apierrors.APIStatus).Status().Details.Causes {
+				fields = append(fields, cause.Field)
+			}
+			g.Expect(fields).Should(Equal(tt.wantFields))
+		})
+	}
+}
+
+func TestMonkeyValidator_ValidateUpdate(t *testing.T) {
+	g := NewWithT(t)
+	validator := &MonkeyValidator{}
+	old := &Monkey{
+		ObjectMeta: metav1.ObjectMeta{Name: "monkey", Namespace: "workloads"},
+		Spec:       MonkeySpec{Selector: metav1.LabelSelector{MatchLabels: map[string]string{"chaosAllowed": "true"}}},
+	}
+	updated := old.DeepCopy()
+	g.Expect(validator.ValidateUpdate(context.Background(), old, updated)).To(Succeed())
+	updated.Spec.Interval = "later"
+	g.Expect(validator.ValidateUpdate(context.Background(), old, updated)).ToNot(Succeed())
+	g.Expect(validator.ValidateDelete(context.Background(), updated)).To(Succeed())
+}
+
+func TestMonkey_Default(t *testing.T) {
+	immediate := DefaultGracePeriodSeconds
+	graceful := int64(10)
+	tests := []struct {
+		name string
+		spec MonkeySpec
+		want MonkeySpec
+	}{
+		{
+			name: "empty",
+			spec: MonkeySpec{},
+			want: MonkeySpec{Interval: "30s", Mode: KillModeOne, GracePeriodSeconds: &immediate, Namespace: "workloads"},
+		},
+		{
+			name: "set",
+			spec: MonkeySpec{Interval: "5m", Mode: KillModeAll, GracePeriodSeconds: &graceful, Namespace: "other"},
+			want: MonkeySpec{Interval: "5m", Mode: KillModeAll, GracePeriodSeconds: &graceful, Namespace: "other"},
+		},
+		{
+			name: "schedule",
+			spec: MonkeySpec{Schedule: "0 9 * * 1-5"},
+			want: MonkeySpec{Schedule: "0 9 * * 1-5", Mode: KillModeOne, GracePeriodSeconds: &immediate, Namespace: "workloads"},
+		},
+		{
+			name: "namespaces",
+			spec: MonkeySpec{Namespaces: []string{"other"}},
+			want: MonkeySpec{Interval: "30s", Mode: KillModeOne, GracePeriodSeconds: &immediate, Namespaces: []string{"other"}},
+		},
+		{
+			name: "namespace selector",
+			spec: MonkeySpec{NamespaceSelector: &metav1.LabelSelector{}},
+			want: MonkeySpec{Interval: "30s", Mode: KillModeOne, GracePeriodSeconds: &immediate, NamespaceSelector: &metav1.LabelSelector{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			monkey := &Monkey{
+				ObjectMeta: metav1.ObjectMeta{Name: "monkey", Namespace: "workloads"},
+				Spec:       tt.spec,
+			}
+			monkey.Default()
+			g.Expect(monkey.Spec).Should(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	podchaosv1alpha1 "github.com/perithompson/podchaosmonkey/api/v1alpha1"
+)
+
+//IsHalted returns true when the abort policy has stopped the monkey
+func IsHalted(monkey *podchaosv1alpha1.Monkey) bool {
+	return meta.IsStatusConditionTrue(monkey.Status.Conditions, podchaosv1alpha1.ConditionHalted)
+}
+
+//halt stops the monkey until it is resumed with the resume annotation
+func (r *MonkeyReconciler) halt(monkey *podchaosv1alpha1.Monkey, reason, message string) {
+	monkeySay := ctrl.Log.WithName("controller").WithName("monkey")
+	message = fmt.Sprintf("Halted by abort policy: %s, add the %s annotation to resume", message, podchaosv1alpha1.ResumeAnnotation)
+	if setCondition(monkey, podchaosv1alpha1.ConditionHalted, metav1.ConditionTrue, reason, message) {
+		monkeySay.Info(message)
+		r.recordEvent(monkey, corev1.EventTypeWarning, EventReasonHalted, message)
+	}
+}
+
+//GetUnreadyPods lists the pods matching the namespaces and labelselector provided that are not ready, ignoring
+//terminating pods, pods that have finished running and pods excluded by the exclude selector or annotation
+func (r *MonkeyReconciler) GetUnreadyPods(ctx context.Context, namespaces []string, labelSelector metav1.LabelSelector, excludeSelector *metav1.LabelSelector) ([]string, error) {
+	var exclude labels.Selector
+	if excludeSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(excludeSelector)
+		if err != nil {
+			return nil, err
+		}
+		exclude = selector
+	}
+	candidates, err := r.GetCandidates(ctx, namespaces, labelSelector)
+	if err != nil {
+		return nil, err
+	}
+	var unready []string
+	for i := range candidates {
+		pod := &candidates[i]
+		if pod.GetDeletionTimestamp() != nil || IsExcluded(pod, exclude) {
+			continue
+		}
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if !IsPodReady(pod) {
+			unready = append(unready, client.ObjectKeyFromObject(pod).String())
+		}
+	}
+	return unready, nil
+}
+
+//CheckAbortPolicy halts the monkey when more pods matching its selector are unready than its abort policy allows,
+//returning true when the monkey was halted
+func (r *MonkeyReconciler) CheckAbortPolicy(ctx context.Context, monkey *podchaosv1alpha1.Monkey, namespaces []string) (bool, error) {
+	policy := monkey.Spec.AbortPolicy
+	if policy == nil || policy.MaxUnreadyPods == nil {
+		return false, nil
+	}
+	unready, err := r.GetUnreadyPods(ctx, namespaces, monkey.Spec.Selector, monkey.Spec.ExcludeSelector)
+	if err != nil {
+		return false, err
+	}
+	if int32(len(unready)) <= *policy.MaxUnreadyPods {
+		return false, nil
+	}
+	r.halt(monkey, podchaosv1alpha1.ReasonUnreadyPods, fmt.Sprintf("%d pods matching the selector are not ready, at most %d allowed", len(unready), *policy.MaxUnreadyPods))
+	return true, nil
+}
+
+//ResumeIfRequested resumes a halted monkey that has the resume annotation and removes the annotation, returning true
+//when the monkey was resumed
+func (r *MonkeyReconciler) ResumeIfRequested(ctx context.Context, monkey *podchaosv1alpha1.Monkey) (bool, error) {
+	if _, ok := monkey.GetAnnotations()[podchaosv1alpha1.ResumeAnnotation]; !ok {
+		return false, nil
+	}
+	resumed := false
+	if IsHalted(monkey) {
+		monkeySay := ctrl.Log.WithName("controller").WithName("monkey")
+		next, err := GetNextExperimentTime(monkey.Spec, time.Now())
+		if err != nil {
+			return false, err
+		}
+		clearCondition(monkey, podchaosv1alpha1.ConditionHalted, podchaosv1alpha1.ReasonResumed)
+		monkey.Status.ConsecutiveRecoveryFailures = 0
+		monkey.Status.NextExperimentTime = &metav1.Time{Time: next}
+		if _, err := r.UpdateStatus(ctx, monkey); err != nil {
+			return false, err
+		}
+		monkeySay.Info(fmt.Sprintf("Resumed monkey: %s", client.ObjectKeyFromObject(monkey)))
+		r.recordEvent(monkey, corev1.EventTypeNormal, EventReasonResumed, "Resumed by the %s annotation", podchaosv1alpha1.ResumeAnnotation)
+		resumed = true
+	}
+	patch := client.MergeFrom(monkey.DeepCopy())
+	delete(monkey.Annotations, podchaosv1alpha1.ResumeAnnotation)
+	return resumed, r.Patch(ctx, monkey, patch)
+}
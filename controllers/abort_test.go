@@ -0,0 +1,167 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	podchaosv1alpha1 "github.com/perithompson/podchaosmonkey/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestMonkeyReconciler_CheckAbortPolicy(t *testing.T) {
+	zero := int32(0)
+	one := int32(1)
+	tests := []struct {
+		name       string
+		policy     *podchaosv1alpha1.AbortPolicy
+		wantHalted bool
+	}{
+		{name: "no policy", policy: nil, wantHalted: false},
+		{name: "no unready threshold", policy: &podchaosv1alpha1.AbortPolicy{MaxConsecutiveRecoveryFailures: 1}, wantHalted: false},
+		{name: "within threshold", policy: &podchaosv1alpha1.AbortPolicy{MaxUnreadyPods: &one}, wantHalted: false},
+		{name: "threshold exceeded", policy: &podchaosv1alpha1.AbortPolicy{MaxUnreadyPods: &zero}, wantHalted: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ready := ReplicaPod("nginx-a", "1", "workloads", "ReplicaSet", "nginx", true)
+			unready := ReplicaPod("nginx-b", "2", "workloads", "ReplicaSet", "nginx", false)
+			c, fakeScheme := InitTests(t, ready, unready)
+			r := &MonkeyReconciler{
+				Client: c,
+				Scheme: fakeScheme,
+			}
+			monkey := Monkey("abort", "5m", "workloads", false, map[string]string{}, []metav1.Condition{})
+			monkey.Spec.AbortPolicy = tt.policy
+
+			halted, err := r.CheckAbortPolicy(context.Background(), monkey, []string{"workloads"})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(halted).Should(Equal(tt.wantHalted))
+			g.Expect(IsHalted(monkey)).Should(Equal(tt.wantHalted))
+			if tt.wantHalted {
+				g.Expect(meta.FindStatusCondition(monkey.Status.Conditions, podchaosv1alpha1.ConditionHalted).Reason).Should(Equal(podchaosv1alpha1.ReasonUnreadyPods))
+			}
+		})
+	}
+}
+
+func TestMonkeyReconciler_GetUnreadyPods(t *testing.T) {
+	g := NewWithT(t)
+	ready := ReplicaPod("nginx-a", "1", "workloads", "ReplicaSet", "nginx", true)
+	unready := ReplicaPod("nginx-b", "2", "workloads", "ReplicaSet", "nginx", false)
+	migration := ReplicaPod("migration", "3", "workloads", "Job", "migration", false)
+	migration.Status.Phase = corev1.PodSucceeded
+	crashed := ReplicaPod("crashed", "4", "workloads", "Job", "report", false)
+	crashed.Status.Phase = corev1.PodFailed
+	annotated := ReplicaPod("annotated", "5", "workloads", "ReplicaSet", "nginx", false)
+	annotated.Annotations = map[string]string{podchaosv1alpha1.ExcludeAnnotation: "true"}
+	leader := ReplicaPod("leader", "6", "workloads", "StatefulSet", "leader", false)
+	leader.Labels = map[string]string{"role": "leader"}
+	terminating := ReplicaPod("terminating", "7", "workloads", "ReplicaSet", "nginx", false)
+	terminating.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	c, fakeScheme := InitTests(t, ready, unready, migration, crashed, annotated, leader, terminating)
+	r := &MonkeyReconciler{
+		Client: c,
+		Scheme: fakeScheme,
+	}
+
+	got, err := r.GetUnreadyPods(context.Background(), []string{"workloads"}, metav1.LabelSelector{},
+		&metav1.LabelSelector{MatchLabels: map[string]string{"role": "leader"}})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).Should(ConsistOf("workloads/nginx-b"))
+
+	// a finished migration Job sharing the selector labels never halts the monkey
+	g.Expect(c.Delete(context.Background(), unready)).To(Succeed())
+	zero := int32(0)
+	monkey := Monkey("abort", "5m", "workloads", false, map[string]string{}, []metav1.Condition{})
+	monkey.Spec.ExcludeSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"role": "leader"}}
+	monkey.Spec.AbortPolicy = &podchaosv1alpha1.AbortPolicy{MaxUnreadyPods: &zero}
+	halted, err := r.CheckAbortPolicy(context.Background(), monkey, []string{"workloads"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(halted).Should(BeFalse())
+}
+
+func TestMonkeyReconciler_CheckRecoveriesAbort(t *testing.T) {
+	start := time.Date(2022, time.April, 8, 17, 30, 0, 0, time.UTC)
+	g := NewWithT(t)
+	c, fakeScheme := InitTests(t, ReplicaSet("nginx", "workloads", 1))
+	r := &MonkeyReconciler{
+		Client: c,
+		Scheme: fakeScheme,
+	}
+	monkey := Monkey("abort", "5m", "workloads", false, map[string]string{}, []metav1.Condition{})
+	monkey.Spec.AbortPolicy = &podchaosv1alpha1.AbortPolicy{MaxConsecutiveRecoveryFailures: 2}
+	check := podchaosv1alpha1.RecoveryCheck{
+		Namespace: "workloads",
+		Owner:     podchaosv1alpha1.WorkloadReference{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "nginx", UID: "nginx"},
+		PodName:   "nginx-victim",
+		PodUID:    "1",
+		StartTime: metav1.NewTime(start),
+		Deadline:  metav1.NewTime(start.Add(time.Minute)),
+	}
+
+	for i := 1; i <= 2; i++ {
+		g.Expect(IsHalted(monkey)).Should(BeFalse())
+		monkey.Status.PendingRecoveries = []podchaosv1alpha1.RecoveryCheck{check}
+		_, err := r.CheckRecoveries(context.Background(), monkey, start.Add(time.Minute))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(monkey.Status.ConsecutiveRecoveryFailures).Should(Equal(int32(i)))
+	}
+	g.Expect(IsHalted(monkey)).Should(BeTrue())
+	g.Expect(meta.FindStatusCondition(monkey.Status.Conditions, podchaosv1alpha1.ConditionHalted).Reason).Should(Equal(podchaosv1alpha1.ReasonRecoveryFailures))
+}
+
+func TestMonkeyReconciler_ResumeIfRequested(t *testing.T) {
+	ctx := context.Background()
+	g := NewWithT(t)
+	monkey := Monkey("abort", "5m", "workloads", false, map[string]string{"allowChaos": "true"}, []metav1.Condition{})
+	monkey.Annotations = map[string]string{podchaosv1alpha1.ResumeAnnotation: "true", "keep": "me"}
+	monkey.Status.ConsecutiveRecoveryFailures = 3
+	setCondition(monkey, podchaosv1alpha1.ConditionHalted, metav1.ConditionTrue, podchaosv1alpha1.ReasonRecoveryFailures, "halted")
+	pod := Pod("delete", "1", "workloads", "true")
+	c, fakeScheme := InitTests(t, monkey.DeepCopy(), &pod)
+	r := &MonkeyReconciler{
+		Client: c,
+		Scheme: fakeScheme,
+	}
+
+	_, err := r.PerformExperiment(ctx, monkey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(&pod), &corev1.Pod{})).To(Succeed())
+
+	resumed, err := r.ResumeIfRequested(ctx, monkey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(resumed).Should(BeTrue())
+
+	stored := &podchaosv1alpha1.Monkey{}
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(monkey), stored)).To(Succeed())
+	g.Expect(IsHalted(stored)).Should(BeFalse())
+	g.Expect(stored.Status.ConsecutiveRecoveryFailures).Should(BeZero())
+	g.Expect(stored.Status.NextExperimentTime).ShouldNot(BeNil())
+	g.Expect(stored.Annotations).Should(Equal(map[string]string{"keep": "me"}))
+
+	resumed, err = r.ResumeIfRequested(ctx, stored)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(resumed).Should(BeFalse())
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	podchaosv1alpha1 "github.com/perithompson/podchaosmonkey/api/v1alpha1"
+)
+
+//GetStartTime returns when the monkey was registered, falling back to when it was created
+func GetStartTime(monkey *podchaosv1alpha1.Monkey) time.Time {
+	if monkey.Status.StartTime != nil {
+		return monkey.Status.StartTime.Time
+	}
+	return monkey.GetCreationTimestamp().Time
+}
+
+//GetEndTime returns when the duration of the monkey elapses, ok is false when the monkey has no duration
+func GetEndTime(monkey *podchaosv1alpha1.Monkey) (end time.Time, ok bool, err error) {
+	if monkey.Spec.Duration == "" {
+		return time.Time{}, false, nil
+	}
+	duration, err := time.ParseDuration(monkey.Spec.Duration)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return GetStartTime(monkey).Add(duration), true, nil
+}
+
+//GetRemainingKills returns how many more pods the monkey may delete before it completes, ok is false when the monkey
+//has no maxKills
+func GetRemainingKills(monkey *podchaosv1alpha1.Monkey) (remaining int64, ok bool) {
+	if monkey.Spec.MaxKills <= 0 {
+		return 0, false
+	}
+	if remaining = monkey.Spec.MaxKills - monkey.Status.TotalKills; remaining < 0 {
+		remaining = 0
+	}
+	return remaining, true
+}
+
+//CheckCompletion sets the Completed condition once the monkey reached its duration or maxKills, clearing it again if the
+//limits were raised. It returns true when the monkey is completed
+func (r *MonkeyReconciler) CheckCompletion(monkey *podchaosv1alpha1.Monkey, now time.Time) (bool, error) {
+	var reason, message string
+	end, hasEnd, err := GetEndTime(monkey)
+	if err != nil {
+		return false, err
+	}
+	if remaining, ok := GetRemainingKills(monkey); ok && remaining == 0 {
+		reason, message = podchaosv1alpha1.ReasonMaxKillsReached, fmt.Sprintf("Completed after deleting %d pods", monkey.Status.TotalKills)
+	} else if hasEnd && !now.Before(end) {
+		reason, message = podchaosv1alpha1.ReasonDurationElapsed, fmt.Sprintf("Completed after running for %s", monkey.Spec.Duration)
+	}
+	if reason == "" {
+		clearCondition(monkey, podchaosv1alpha1.ConditionCompleted, podchaosv1alpha1.ReasonRunning)
+		return false, nil
+	}
+	if setCondition(monkey, podchaosv1alpha1.ConditionCompleted, metav1.ConditionTrue, reason, message) {
+		ctrl.Log.WithName("controller").WithName("monkey").Info(message)
+		r.recordEvent(monkey, corev1.EventTypeNormal, EventReasonCompleted, message)
+		monkey.Status.NextExperimentTime = nil
+	}
+	return true, nil
+}
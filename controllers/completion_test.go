@@ -0,0 +1,123 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	podchaosv1alpha1 "github.com/perithompson/podchaosmonkey/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestMonkeyReconciler_CheckCompletion(t *testing.T) {
+	start := time.Date(2022, time.April, 8, 17, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		duration      string
+		maxKills      int64
+		totalKills    int64
+		completed     bool
+		now           time.Time
+		want          bool
+		wantReason    string
+		wantErr       bool
+		wantCondition bool
+	}{
+		{name: "no limits", totalKills: 100, now: start.Add(24 * time.Hour), want: false},
+		{name: "kills remaining", maxKills: 5, totalKills: 4, now: start, want: false},
+		{name: "max kills reached", maxKills: 5, totalKills: 5, now: start, want: true, wantReason: podchaosv1alpha1.ReasonMaxKillsReached, wantCondition: true},
+		{name: "duration running", duration: "1h", now: start.Add(59 * time.Minute), want: false},
+		{name: "duration elapsed", duration: "1h", now: start.Add(time.Hour), want: true, wantReason: podchaosv1alpha1.ReasonDurationElapsed, wantCondition: true},
+		{name: "limits raised", maxKills: 10, totalKills: 5, completed: true, now: start, want: false, wantReason: "Running", wantCondition: true},
+		{name: "invalid duration", duration: "a while", now: start, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			r := &MonkeyReconciler{}
+			monkey := Monkey("complete", "5m", "workloads", false, map[string]string{}, []metav1.Condition{})
+			monkey.Spec.Duration = tt.duration
+			monkey.Spec.MaxKills = tt.maxKills
+			monkey.Status.TotalKills = tt.totalKills
+			monkey.Status.StartTime = &metav1.Time{Time: start}
+			if tt.completed {
+				setCondition(monkey, podchaosv1alpha1.ConditionCompleted, metav1.ConditionTrue, podchaosv1alpha1.ReasonMaxKillsReached, "")
+			}
+
+			got, err := r.CheckCompletion(monkey, tt.now)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).Should(Equal(tt.want))
+			condition := meta.FindStatusCondition(monkey.Status.Conditions, podchaosv1alpha1.ConditionCompleted)
+			if !tt.wantCondition {
+				g.Expect(condition).Should(BeNil())
+				return
+			}
+			g.Expect(condition.Status == metav1.ConditionTrue).Should(Equal(tt.want))
+			g.Expect(condition.Reason).Should(Equal(tt.wantReason))
+		})
+	}
+}
+
+func TestMonkeyReconciler_PerformExperimentMaxKills(t *testing.T) {
+	ctx := context.Background()
+	g := NewWithT(t)
+	monkey := Monkey("complete", "5m", "workloads", false, map[string]string{"allowChaos": "true"}, []metav1.Condition{})
+	monkey.Spec.Mode = podchaosv1alpha1.KillModeAll
+	monkey.Spec.MaxKills = 5
+	monkey.Status.TotalKills = 2
+	objs := []client.Object{monkey.DeepCopy()}
+	for i := 0; i < 10; i++ {
+		pod := Pod(fmt.Sprintf("pod-%d", i), fmt.Sprintf("%d", i), "workloads", "true")
+		objs = append(objs, &pod)
+	}
+	c, fakeScheme := InitTests(t, objs...)
+	r := &MonkeyReconciler{
+		Client: c,
+		Scheme: fakeScheme,
+	}
+
+	result, err := r.PerformExperiment(ctx, monkey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.RequeueAfter).Should(BeZero())
+
+	pods := &corev1.PodList{}
+	g.Expect(r.List(ctx, pods)).To(Succeed())
+	g.Expect(pods.Items).Should(HaveLen(7))
+	stored := &podchaosv1alpha1.Monkey{}
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(monkey), stored)).To(Succeed())
+	g.Expect(stored.Status.TotalKills).Should(Equal(int64(5)))
+	g.Expect(meta.IsStatusConditionTrue(stored.Status.Conditions, podchaosv1alpha1.ConditionCompleted)).Should(BeTrue())
+	g.Expect(stored.Status.NextExperimentTime).Should(BeNil())
+
+	// A completed monkey does not delete any more pods
+	result, err = r.PerformExperiment(ctx, stored)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.RequeueAfter).Should(BeZero())
+	g.Expect(r.List(ctx, pods)).To(Succeed())
+	g.Expect(pods.Items).Should(HaveLen(7))
+}
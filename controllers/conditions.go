@@ -0,0 +1,108 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	podchaosv1alpha1 "github.com/perithompson/podchaosmonkey/api/v1alpha1"
+)
+
+// conditionRegistered was set once when a monkey was first reconciled by earlier versions of the controller
+const conditionRegistered = "Registered"
+
+// degradingConditions mark a monkey as degraded while any of them is true
+var degradingConditions = []string{
+	podchaosv1alpha1.ConditionHalted,
+	podchaosv1alpha1.ConditionRefused,
+	podchaosv1alpha1.ConditionEvictionBlocked,
+}
+
+// inactiveConditions mark a monkey as inactive while any of them is true, in order of precedence
+var inactiveConditions = []string{
+	podchaosv1alpha1.ConditionInvalidSpec,
+	podchaosv1alpha1.ConditionHalted,
+	podchaosv1alpha1.ConditionCompleted,
+	podchaosv1alpha1.ConditionSuspended,
+	podchaosv1alpha1.ConditionRefused,
+}
+
+//setCondition sets a condition on the monkey status as observed at the monkey's current generation, returning true
+//when the status changed
+func setCondition(monkey *podchaosv1alpha1.Monkey, conditionType string, status metav1.ConditionStatus, reason, message string) bool {
+	existing := meta.FindStatusCondition(monkey.Status.Conditions, conditionType)
+	if existing != nil && existing.Status == status && existing.Reason == reason && existing.Message == message {
+		existing.ObservedGeneration = monkey.GetGeneration()
+		return false
+	}
+	meta.SetStatusCondition(&monkey.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: monkey.GetGeneration(),
+		Reason:             reason,
+		Message:            message,
+	})
+	return true
+}
+
+//clearCondition sets a condition on the monkey status to false if it is currently true, returning true when the status
+//changed. A condition that is already false is only marked as observed at the monkey's current generation
+func clearCondition(monkey *podchaosv1alpha1.Monkey, conditionType, reason string) bool {
+	existing := meta.FindStatusCondition(monkey.Status.Conditions, conditionType)
+	if existing == nil {
+		return false
+	}
+	if existing.Status != metav1.ConditionTrue {
+		existing.ObservedGeneration = monkey.GetGeneration()
+		return false
+	}
+	return setCondition(monkey, conditionType, metav1.ConditionFalse, reason, "")
+}
+
+//firstTrueCondition returns a copy of the first of the conditions provided that is true on the monkey
+func firstTrueCondition(monkey *podchaosv1alpha1.Monkey, conditionTypes ...string) (metav1.Condition, bool) {
+	for _, conditionType := range conditionTypes {
+		if condition := meta.FindStatusCondition(monkey.Status.Conditions, conditionType); condition != nil && condition.Status == metav1.ConditionTrue {
+			return *condition, true
+		}
+	}
+	return metav1.Condition{}, false
+}
+
+//SetSummaryConditions derives the Ready, Active, Degraded and InvalidSpec conditions of a monkey from its other conditions
+func SetSummaryConditions(monkey *podchaosv1alpha1.Monkey) {
+	meta.RemoveStatusCondition(&monkey.Status.Conditions, conditionRegistered)
+	if meta.FindStatusCondition(monkey.Status.Conditions, podchaosv1alpha1.ConditionInvalidSpec) == nil {
+		setCondition(monkey, podchaosv1alpha1.ConditionInvalidSpec, metav1.ConditionFalse, podchaosv1alpha1.ReasonValid, "")
+	}
+	if condition, ok := firstTrueCondition(monkey, degradingConditions...); ok {
+		setCondition(monkey, podchaosv1alpha1.ConditionDegraded, metav1.ConditionTrue, condition.Reason, condition.Message)
+	} else {
+		setCondition(monkey, podchaosv1alpha1.ConditionDegraded, metav1.ConditionFalse, podchaosv1alpha1.ReasonAsExpected, "")
+	}
+	if condition, ok := firstTrueCondition(monkey, inactiveConditions...); ok {
+		setCondition(monkey, podchaosv1alpha1.ConditionActive, metav1.ConditionFalse, condition.Reason, condition.Message)
+	} else {
+		setCondition(monkey, podchaosv1alpha1.ConditionActive, metav1.ConditionTrue, podchaosv1alpha1.ReasonRunning, "")
+	}
+	if condition, ok := firstTrueCondition(monkey, podchaosv1alpha1.ConditionInvalidSpec, podchaosv1alpha1.ConditionDegraded); ok {
+		setCondition(monkey, podchaosv1alpha1.ConditionReady, metav1.ConditionFalse, condition.Reason, condition.Message)
+	} else {
+		setCondition(monkey, podchaosv1alpha1.ConditionReady, metav1.ConditionTrue, podchaosv1alpha1.ReasonReconciled, "")
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	podchaosv1alpha1 "github.com/perithompson/podchaosmonkey/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestSetSummaryConditions(t *testing.T) {
+	tests := []struct {
+		name         string
+		conditions   []metav1.Condition
+		wantReady    metav1.ConditionStatus
+		wantActive   metav1.ConditionStatus
+		wantDegraded metav1.ConditionStatus
+		wantReason   string
+	}{
+		{
+			name:         "running",
+			wantReady:    metav1.ConditionTrue,
+			wantActive:   metav1.ConditionTrue,
+			wantDegraded: metav1.ConditionFalse,
+			wantReason:   podchaosv1alpha1.ReasonRunning,
+		},
+		{
+			name:         "suspended",
+			conditions:   []metav1.Condition{{Type: podchaosv1alpha1.ConditionSuspended, Status: metav1.ConditionTrue, Reason: podchaosv1alpha1.ReasonKillSwitch}},
+			wantReady:    metav1.ConditionTrue,
+			wantActive:   metav1.ConditionFalse,
+			wantDegraded: metav1.ConditionFalse,
+			wantReason:   podchaosv1alpha1.ReasonKillSwitch,
+		},
+		{
+			name:         "completed",
+			conditions:   []metav1.Condition{{Type: podchaosv1alpha1.ConditionCompleted, Status: metav1.ConditionTrue, Reason: podchaosv1alpha1.ReasonMaxKillsReached}},
+			wantReady:    metav1.ConditionTrue,
+			wantActive:   metav1.ConditionFalse,
+			wantDegraded: metav1.ConditionFalse,
+			wantReason:   podchaosv1alpha1.ReasonMaxKillsReached,
+		},
+		{
+			name: "halted",
+			conditions: []metav1.Condition{
+				{Type: podchaosv1alpha1.ConditionSuspended, Status: metav1.ConditionFalse, Reason: podchaosv1alpha1.ReasonActive},
+				{Type: podchaosv1alpha1.ConditionHalted, Status: metav1.ConditionTrue, Reason: podchaosv1alpha1.ReasonUnreadyPods},
+			},
+			wantReady:    metav1.ConditionFalse,
+			wantActive:   metav1.ConditionFalse,
+			wantDegraded: metav1.ConditionTrue,
+			wantReason:   podchaosv1alpha1.ReasonUnreadyPods,
+		},
+		{
+			name:         "eviction blocked",
+			conditions:   []metav1.Condition{{Type: podchaosv1alpha1.ConditionEvictionBlocked, Status: metav1.ConditionTrue, Reason: podchaosv1alpha1.ReasonPodDisruptionBudget}},
+			wantReady:    metav1.ConditionFalse,
+			wantActive:   metav1.ConditionTrue,
+			wantDegraded: metav1.ConditionTrue,
+			wantReason:   podchaosv1alpha1.ReasonRunning,
+		},
+		{
+			name:         "legacy registered condition",
+			conditions:   []metav1.Condition{{Type: conditionRegistered, Status: metav1.ConditionTrue, Reason: conditionRegistered}},
+			wantReady:    metav1.ConditionTrue,
+			wantActive:   metav1.ConditionTrue,
+			wantDegraded: metav1.ConditionFalse,
+			wantReason:   podchaosv1alpha1.ReasonRunning,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			monkey := Monkey("summary", "5m", "workloads", true, nil, tt.conditions)
+			monkey.SetGeneration(4)
+			SetSummaryConditions(monkey)
+			g.Expect(meta.FindStatusCondition(monkey.Status.Conditions, podchaosv1alpha1.ConditionReady).Status).Should(Equal(tt.wantReady))
+			g.Expect(meta.FindStatusCondition(monkey.Status.Conditions, podchaosv1alpha1.ConditionDegraded).Status).Should(Equal(tt.wantDegraded))
+			g.Expect(meta.IsStatusConditionFalse(monkey.Status.Conditions, podchaosv1alpha1.ConditionInvalidSpec)).Should(BeTrue())
+			active := meta.FindStatusCondition(monkey.Status.Conditions, podchaosv1alpha1.ConditionActive)
+			g.Expect(active.Status).Should(Equal(tt.wantActive))
+			g.Expect(active.Reason).Should(Equal(tt.wantReason))
+			g.Expect(meta.FindStatusCondition(monkey.Status.Conditions, conditionRegistered)).Should(BeNil())
+			// only the derived conditions are observed at the current generation, the others keep their own
+			for _, condition := range monkey.Status.Conditions {
+				switch condition.Type {
+				case podchaosv1alpha1.ConditionReady, podchaosv1alpha1.ConditionActive, podchaosv1alpha1.ConditionDegraded, podchaosv1alpha1.ConditionInvalidSpec:
+					g.Expect(condition.ObservedGeneration).Should(Equal(int64(4)))
+				default:
+					g.Expect(condition.ObservedGeneration).Should(BeZero())
+				}
+			}
+		})
+	}
+}
+
+func TestMonkeyReconciler_ReconcileConditions(t *testing.T) {
+	ctx := context.Background()
+	monkey := Monkey("conditions", "5m", "workloads", false, map[string]string{"allowChaos": "true"}, nil)
+	monkey.SetGeneration(1)
+	c, fakeScheme := InitTests(t, monkey)
+	g := NewWithT(t)
+	r := &MonkeyReconciler{
+		Client: c,
+		Scheme: fakeScheme,
+	}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(monkey)}
+
+	_, err := r.Reconcile(ctx, req)
+	g.Expect(err).ToNot(HaveOccurred())
+	stored := &podchaosv1alpha1.Monkey{}
+	g.Expect(r.Get(ctx, req.NamespacedName, stored)).To(Succeed())
+	g.Expect(meta.IsStatusConditionTrue(stored.Status.Conditions, podchaosv1alpha1.ConditionReady)).Should(BeTrue())
+	g.Expect(meta.IsStatusConditionTrue(stored.Status.Conditions, podchaosv1alpha1.ConditionActive)).Should(BeTrue())
+	g.Expect(meta.FindStatusCondition(stored.Status.Conditions, podchaosv1alpha1.ConditionReady).ObservedGeneration).Should(Equal(int64(1)))
+
+	// suspending the monkey in a new generation is reflected by every condition
+	stored.Spec.Suspend = true
+	stored.SetGeneration(2)
+	g.Expect(r.Update(ctx, stored)).To(Succeed())
+	_, err = r.Reconcile(ctx, req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.Get(ctx, req.NamespacedName, stored)).To(Succeed())
+	g.Expect(stored.Status.ObservedGeneration).Should(Equal(int64(2)))
+	active := meta.FindStatusCondition(stored.Status.Conditions, podchaosv1alpha1.ConditionActive)
+	g.Expect(active.Status).Should(Equal(metav1.ConditionFalse))
+	g.Expect(active.Reason).Should(Equal(podchaosv1alpha1.ReasonSuspendedBySpec))
+	for _, condition := range stored.Status.Conditions {
+		g.Expect(condition.ObservedGeneration).Should(Equal(int64(2)))
+	}
+}
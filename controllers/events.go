@@ -0,0 +1,65 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Reasons of the Events emitted on Monkeys and the owners of their victims
+const (
+	EventReasonPodKilled       = "PodKilled"
+	EventReasonNoopKill        = "NoopKill"
+	EventReasonNoTarget        = "NoTarget"
+	EventReasonKillFailed      = "KillFailed"
+	EventReasonEvictionBlocked = "EvictionBlocked"
+	EventReasonRefused         = "Refused"
+	EventReasonSuspended       = "Suspended"
+	EventReasonTargetUnhealthy = "TargetUnhealthy"
+	EventReasonRecovered       = "Recovered"
+	EventReasonRecoveryFailed  = "RecoveryFailed"
+	EventReasonHalted          = "Halted"
+	EventReasonResumed         = "Resumed"
+	EventReasonCompleted       = "Completed"
+	EventReasonInvalidSpec     = "InvalidSpec"
+)
+
+//recordEvent emits an Event on the object provided when the reconciler has an EventRecorder
+func (r *MonkeyReconciler) recordEvent(object runtime.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	if r.Recorder == nil {
+		return
+	}
+	r.Recorder.Eventf(object, eventType, reason, messageFmt, args...)
+}
+
+//recordOwnerEvent emits an Event on the controller owning the pod provided, or on the pod itself when it has no owner
+func (r *MonkeyReconciler) recordOwnerEvent(pod *corev1.Pod, eventType, reason, messageFmt string, args ...interface{}) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		r.recordEvent(pod, eventType, reason, messageFmt, args...)
+		return
+	}
+	r.recordEvent(&corev1.ObjectReference{
+		APIVersion: owner.APIVersion,
+		Kind:       owner.Kind,
+		Name:       owner.Name,
+		Namespace:  pod.GetNamespace(),
+		UID:        owner.UID,
+	}, eventType, reason, messageFmt, args...)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// reasonCrashLoopBackOff is the waiting reason of a container that keeps crashing
+const reasonCrashLoopBackOff = "CrashLoopBackOff"
+
+//IsPodCrashLooping returns true when any container of the pod provided is in CrashLoopBackOff
+func IsPodCrashLooping(pod *corev1.Pod) bool {
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if status.State.Waiting != nil && status.State.Waiting.Reason == reasonCrashLoopBackOff {
+			return true
+		}
+	}
+	return false
+}
+
+//GetUnhealthyReason returns why the workload of the pod provided is not in a steady state, or an empty string when it
+//is healthy. A workload is healthy when all its desired replicas are ready and none of its pods are in CrashLoopBackOff,
+//pods not controlled by a ReplicaSet, StatefulSet or DaemonSet are only checked for CrashLoopBackOff
+func (r *MonkeyReconciler) GetUnhealthyReason(ctx context.Context, pod *corev1.Pod) (string, error) {
+	owner := GetWorkload(pod)
+	if owner == nil {
+		if IsPodCrashLooping(pod) {
+			return fmt.Sprintf("pod %s/%s is in %s", pod.GetNamespace(), pod.GetName(), reasonCrashLoopBackOff), nil
+		}
+		return "", nil
+	}
+	workload := fmt.Sprintf("%s %s/%s", owner.Kind, pod.GetNamespace(), owner.Name)
+	desired, err := r.GetDesiredReplicas(ctx, pod.GetNamespace(), *owner)
+	if apierrors.IsNotFound(err) {
+		return fmt.Sprintf("%s no longer exists", workload), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	pods, err := r.GetWorkloadPods(ctx, pod.GetNamespace(), *owner)
+	if err != nil {
+		return "", err
+	}
+	var ready int32
+	for i := range pods {
+		if IsPodCrashLooping(&pods[i]) {
+			return fmt.Sprintf("%s has pod %s in %s", workload, pods[i].GetName(), reasonCrashLoopBackOff), nil
+		}
+		if IsPodReady(&pods[i]) {
+			ready++
+		}
+	}
+	if ready < desired {
+		return fmt.Sprintf("%s has %d of %d replicas ready", workload, ready, desired), nil
+	}
+	return "", nil
+}
+
+//GetUnhealthyTargets returns why the workloads of the pods provided are not in a steady state, checking each workload once
+func (r *MonkeyReconciler) GetUnhealthyTargets(ctx context.Context, targets []corev1.Pod) ([]string, error) {
+	var reasons []string
+	checked := map[types.UID]bool{}
+	for i := range targets {
+		if owner := GetWorkload(&targets[i]); owner != nil {
+			if checked[owner.UID] {
+				continue
+			}
+			checked[owner.UID] = true
+		}
+		reason, err := r.GetUnhealthyReason(ctx, &targets[i])
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			reasons = append(reasons, reason)
+		}
+	}
+	return reasons, nil
+}
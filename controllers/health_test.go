@@ -0,0 +1,149 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	podchaosv1alpha1 "github.com/perithompson/podchaosmonkey/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func CrashLoopingPod(pod *corev1.Pod) *corev1.Pod {
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:  "app",
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reasonCrashLoopBackOff}},
+	}}
+	return pod
+}
+
+func TestMonkeyReconciler_GetUnhealthyReason(t *testing.T) {
+	orphan := Pod("orphan", "9", "workloads", "true")
+	tests := []struct {
+		name   string
+		target *corev1.Pod
+		objs   []client.Object
+		want   string
+	}{
+		{
+			name:   "healthy",
+			target: ReplicaPod("nginx-a", "1", "workloads", "ReplicaSet", "nginx", true),
+			objs: []client.Object{
+				ReplicaSet("nginx", "workloads", 2),
+				ReplicaPod("nginx-a", "1", "workloads", "ReplicaSet", "nginx", true),
+				ReplicaPod("nginx-b", "2", "workloads", "ReplicaSet", "nginx", true),
+			},
+			want: "",
+		},
+		{
+			name:   "replicas not ready",
+			target: ReplicaPod("nginx-a", "1", "workloads", "ReplicaSet", "nginx", true),
+			objs: []client.Object{
+				ReplicaSet("nginx", "workloads", 2),
+				ReplicaPod("nginx-a", "1", "workloads", "ReplicaSet", "nginx", true),
+				ReplicaPod("nginx-b", "2", "workloads", "ReplicaSet", "nginx", false),
+			},
+			want: "ReplicaSet workloads/nginx has 1 of 2 replicas ready",
+		},
+		{
+			name:   "crash looping",
+			target: ReplicaPod("nginx-a", "1", "workloads", "ReplicaSet", "nginx", true),
+			objs: []client.Object{
+				ReplicaSet("nginx", "workloads", 1),
+				ReplicaPod("nginx-a", "1", "workloads", "ReplicaSet", "nginx", true),
+				CrashLoopingPod(ReplicaPod("nginx-b", "2", "workloads", "ReplicaSet", "nginx", false)),
+			},
+			want: "ReplicaSet workloads/nginx has pod nginx-b in CrashLoopBackOff",
+		},
+		{
+			name:   "workload deleted",
+			target: ReplicaPod("nginx-a", "1", "workloads", "ReplicaSet", "nginx", true),
+			objs:   []client.Object{},
+			want:   "ReplicaSet workloads/nginx no longer exists",
+		},
+		{
+			name:   "orphan pod",
+			target: orphan.DeepCopy(),
+			objs:   []client.Object{},
+			want:   "",
+		},
+		{
+			name:   "orphan pod crash looping",
+			target: CrashLoopingPod(orphan.DeepCopy()),
+			objs:   []client.Object{},
+			want:   "pod workloads/orphan is in CrashLoopBackOff",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			c, fakeScheme := InitTests(t, tt.objs...)
+			r := &MonkeyReconciler{
+				Client: c,
+				Scheme: fakeScheme,
+			}
+			got, err := r.GetUnhealthyReason(context.Background(), tt.target)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).Should(Equal(tt.want))
+		})
+	}
+}
+
+func TestMonkeyReconciler_GetUnhealthyTargets(t *testing.T) {
+	g := NewWithT(t)
+	targets := []corev1.Pod{
+		*ReplicaPod("nginx-a", "1", "workloads", "ReplicaSet", "nginx", true),
+		*ReplicaPod("nginx-b", "2", "workloads", "ReplicaSet", "nginx", false),
+	}
+	c, fakeScheme := InitTests(t, ReplicaSet("nginx", "workloads", 2), &targets[0], &targets[1])
+	r := &MonkeyReconciler{
+		Client: c,
+		Scheme: fakeScheme,
+	}
+	got, err := r.GetUnhealthyTargets(context.Background(), targets)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).Should(Equal([]string{"ReplicaSet workloads/nginx has 1 of 2 replicas ready"}))
+}
+
+func TestMonkeyReconciler_PerformExperimentUnhealthy(t *testing.T) {
+	ctx := context.Background()
+	g := NewWithT(t)
+	monkey := Monkey("unhealthy", "5m", "workloads", false, map[string]string{"allowChaos": "true"}, []metav1.Condition{})
+	pod := ReplicaPod("nginx-a", "1", "workloads", "ReplicaSet", "nginx", true)
+	pod.Labels = map[string]string{"allowChaos": "true"}
+	c, fakeScheme := InitTests(t, monkey.DeepCopy(), pod, ReplicaSet("nginx", "workloads", 2))
+	r := &MonkeyReconciler{
+		Client: c,
+		Scheme: fakeScheme,
+	}
+
+	_, err := r.PerformExperiment(ctx, monkey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(pod), &corev1.Pod{})).To(Succeed())
+
+	stored := &podchaosv1alpha1.Monkey{}
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(monkey), stored)).To(Succeed())
+	g.Expect(meta.IsStatusConditionTrue(stored.Status.Conditions, podchaosv1alpha1.ConditionTargetUnhealthy)).Should(BeTrue())
+	g.Expect(stored.Status.LastVictims).Should(BeEmpty())
+	g.Expect(stored.Status.History).Should(HaveLen(1))
+	g.Expect(stored.Status.History[0].Result).Should(Equal(podchaosv1alpha1.ResultSkipped))
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	podchaosv1alpha1 "github.com/perithompson/podchaosmonkey/api/v1alpha1"
+)
+
+// HistoryLimit is the number of experiment records kept in the status of a Monkey
+const HistoryLimit = 10
+
+//NewExperimentRecord returns a record of the action the monkey takes against the pod provided
+func NewExperimentRecord(monkey *podchaosv1alpha1.Monkey, pod *corev1.Pod, now time.Time) podchaosv1alpha1.ExperimentRecord {
+	record := podchaosv1alpha1.ExperimentRecord{
+		Time:      metav1.NewTime(now),
+		Namespace: pod.GetNamespace(),
+		Name:      pod.GetName(),
+		UID:       pod.GetUID(),
+		Node:      pod.Spec.NodeName,
+		Action:    podchaosv1alpha1.ActionDelete,
+		Noop:      monkey.Spec.Noop,
+	}
+	if owner := metav1.GetControllerOf(pod); owner != nil {
+		record.Owner = fmt.Sprintf("%s/%s", owner.Kind, owner.Name)
+	}
+	if monkey.Spec.UseEviction {
+		record.Action = podchaosv1alpha1.ActionEvict
+	}
+	return record
+}
+
+//RecordExperiment adds the record provided to the history of the monkey, dropping the oldest records beyond the HistoryLimit
+func RecordExperiment(monkey *podchaosv1alpha1.Monkey, record podchaosv1alpha1.ExperimentRecord) {
+	monkey.Status.History = append(monkey.Status.History, record)
+	if overflow := len(monkey.Status.History) - HistoryLimit; overflow > 0 {
+		monkey.Status.History = append([]podchaosv1alpha1.ExperimentRecord{}, monkey.Status.History[overflow:]...)
+	}
+	if record.Result == podchaosv1alpha1.ResultSucceeded && !record.Noop {
+		monkey.Status.TotalKills++
+		monkey.Status.LastKillTime = record.Time.DeepCopy()
+	}
+}
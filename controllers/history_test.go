@@ -0,0 +1,89 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	podchaosv1alpha1 "github.com/perithompson/podchaosmonkey/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewExperimentRecord(t *testing.T) {
+	now := time.Date(2022, time.April, 8, 17, 30, 0, 0, time.UTC)
+	pod := Pod("nginx-66b6c48dd5-abcde", "1", "workloads", "true")
+	pod.Spec.NodeName = "worker-1"
+	pod.OwnerReferences = []metav1.OwnerReference{
+		{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "nginx-66b6c48dd5", Controller: func() *bool { b := true; return &b }()},
+	}
+	g := NewWithT(t)
+
+	monkey := Monkey("test", "5m", "workloads", false, map[string]string{}, []metav1.Condition{})
+	g.Expect(NewExperimentRecord(monkey, &pod, now)).Should(Equal(podchaosv1alpha1.ExperimentRecord{
+		Time:      metav1.NewTime(now),
+		Namespace: "workloads",
+		Name:      "nginx-66b6c48dd5-abcde",
+		UID:       "1",
+		Node:      "worker-1",
+		Owner:     "ReplicaSet/nginx-66b6c48dd5",
+		Action:    podchaosv1alpha1.ActionDelete,
+	}))
+
+	monkey.Spec.Noop = true
+	monkey.Spec.UseEviction = true
+	orphan := Pod("orphan", "2", "workloads", "true")
+	record := NewExperimentRecord(monkey, &orphan, now)
+	g.Expect(record.Action).Should(Equal(podchaosv1alpha1.ActionEvict))
+	g.Expect(record.Noop).Should(BeTrue())
+	g.Expect(record.Owner).Should(BeEmpty())
+}
+
+func TestRecordExperiment(t *testing.T) {
+	start := time.Date(2022, time.April, 8, 17, 30, 0, 0, time.UTC)
+	monkey := Monkey("test", "5m", "workloads", false, map[string]string{}, []metav1.Condition{})
+	g := NewWithT(t)
+
+	for i := 0; i < HistoryLimit+5; i++ {
+		result := podchaosv1alpha1.ResultSucceeded
+		if i%3 == 0 {
+			result = podchaosv1alpha1.ResultFailed
+		}
+		RecordExperiment(monkey, podchaosv1alpha1.ExperimentRecord{
+			Time:               metav1.NewTime(start.Add(time.Duration(i) * time.Minute)),
+			Name:               fmt.Sprintf("pod-%d", i),
+			GracePeriodSeconds: int64ToPointerint64(int64(i)),
+			Result:             result,
+		})
+	}
+	RecordExperiment(monkey, podchaosv1alpha1.ExperimentRecord{
+		Time:   metav1.NewTime(start.Add(time.Hour)),
+		Name:   "noop",
+		Noop:   true,
+		Result: podchaosv1alpha1.ResultSucceeded,
+	})
+
+	g.Expect(monkey.Status.History).Should(HaveLen(HistoryLimit))
+	g.Expect(monkey.Status.History[0].Name).Should(Equal("pod-6"))
+	g.Expect(monkey.Status.History[0].GracePeriodSeconds).Should(Equal(int64ToPointerint64(6)))
+	g.Expect(monkey.Status.History[HistoryLimit-1].GracePeriodSeconds).Should(BeNil())
+	g.Expect(monkey.Status.History[HistoryLimit-1].Name).Should(Equal("noop"))
+	g.Expect(monkey.Status.TotalKills).Should(Equal(int64(10)))
+	g.Expect(monkey.Status.LastKillTime.Time).Should(Equal(start.Add(14 * time.Minute)))
+}
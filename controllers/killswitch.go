@@ -0,0 +1,101 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	podchaosv1alpha1 "github.com/perithompson/podchaosmonkey/api/v1alpha1"
+)
+
+// KillSwitchKey is the key of the kill switch ConfigMap that suspends every Monkey when set to "true"
+const KillSwitchKey = "suspend"
+
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+
+//IsKillSwitchActive returns true when the kill switch ConfigMap exists and suspends every monkey
+func (r *MonkeyReconciler) IsKillSwitchActive(ctx context.Context) (bool, error) {
+	if r.KillSwitch.Name == "" {
+		return false, nil
+	}
+	configMap := &corev1.ConfigMap{}
+	if err := r.Get(ctx, r.KillSwitch, configMap); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return configMap.Data[KillSwitchKey] == "true", nil
+}
+
+//CheckKillSwitch marks the monkey as suspended while the kill switch is active and clears the suspension once it is
+//released, planning the next experiment from the time provided rather than running the experiments missed while it was
+//active straight away. It returns true when the kill switch is active
+func (r *MonkeyReconciler) CheckKillSwitch(ctx context.Context, monkey *podchaosv1alpha1.Monkey, now time.Time) (bool, error) {
+	active, err := r.IsKillSwitchActive(ctx)
+	if err != nil {
+		return false, err
+	}
+	suspended := meta.FindStatusCondition(monkey.Status.Conditions, podchaosv1alpha1.ConditionSuspended)
+	if !active {
+		if suspended != nil && suspended.Status == metav1.ConditionTrue && suspended.Reason == podchaosv1alpha1.ReasonKillSwitch {
+			next, err := GetNextExperimentTime(monkey.Spec, now)
+			if err != nil {
+				return false, err
+			}
+			clearCondition(monkey, podchaosv1alpha1.ConditionSuspended, podchaosv1alpha1.ReasonActive)
+			monkey.Status.NextExperimentTime = &metav1.Time{Time: next}
+			ctrl.Log.WithName("controller").WithName("monkey").Info(fmt.Sprintf("Resumed monkey: %s", client.ObjectKeyFromObject(monkey)))
+			r.recordEvent(monkey, corev1.EventTypeNormal, EventReasonResumed, "Resumed after the kill switch ConfigMap %s was released", r.KillSwitch)
+			_, err = r.UpdateStatus(ctx, monkey)
+			return false, err
+		}
+		return false, nil
+	}
+	message := fmt.Sprintf("Chaos suspended by the kill switch ConfigMap %s", r.KillSwitch)
+	if setCondition(monkey, podchaosv1alpha1.ConditionSuspended, metav1.ConditionTrue, podchaosv1alpha1.ReasonKillSwitch, message) {
+		ctrl.Log.WithName("controller").WithName("monkey").Info(message)
+		r.recordEvent(monkey, corev1.EventTypeWarning, EventReasonSuspended, message)
+		if _, err := r.UpdateStatus(ctx, monkey); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+//mapKillSwitch requests a reconcile of every monkey when the kill switch ConfigMap changes
+func (r *MonkeyReconciler) mapKillSwitch(object client.Object) []reconcile.Request {
+	if client.ObjectKeyFromObject(object) != r.KillSwitch {
+		return nil
+	}
+	var list podchaosv1alpha1.MonkeyList
+	if err := r.List(context.Background(), &list); err != nil {
+		ctrl.Log.WithName("controller").WithName("monkey").Error(err, "Unable to list monkeys for the kill switch")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(list.Items))
+	for _, monkey := range list.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&monkey)})
+	}
+	return requests
+}
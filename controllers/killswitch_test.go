@@ -0,0 +1,135 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	podchaosv1alpha1 "github.com/perithompson/podchaosmonkey/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var killSwitchKey = types.NamespacedName{Namespace: "podchaosmonkey-system", Name: "podchaosmonkey-kill-switch"}
+
+func KillSwitch(suspend string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: killSwitchKey.Name, Namespace: killSwitchKey.Namespace},
+		Data:       map[string]string{KillSwitchKey: suspend},
+	}
+}
+
+func TestMonkeyReconciler_IsKillSwitchActive(t *testing.T) {
+	tests := []struct {
+		name       string
+		killSwitch types.NamespacedName
+		objs       []client.Object
+		want       bool
+	}{
+		{name: "not configured", killSwitch: types.NamespacedName{}, objs: []client.Object{KillSwitch("true")}, want: false},
+		{name: "missing", killSwitch: killSwitchKey, want: false},
+		{name: "released", killSwitch: killSwitchKey, objs: []client.Object{KillSwitch("false")}, want: false},
+		{name: "active", killSwitch: killSwitchKey, objs: []client.Object{KillSwitch("true")}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			c, fakeScheme := InitTests(t, tt.objs...)
+			r := &MonkeyReconciler{
+				Client:     c,
+				Scheme:     fakeScheme,
+				KillSwitch: tt.killSwitch,
+			}
+			got, err := r.IsKillSwitchActive(context.Background())
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).Should(Equal(tt.want))
+		})
+	}
+}
+
+func TestMonkeyReconciler_PerformExperimentKillSwitch(t *testing.T) {
+	ctx := context.Background()
+	g := NewWithT(t)
+	monkey := Monkey("killswitch", "5m", "workloads", false, map[string]string{"allowChaos": "true"}, []metav1.Condition{})
+	pod := Pod("delete", "1", "workloads", "true")
+	killSwitch := KillSwitch("true")
+	c, fakeScheme := InitTests(t, monkey.DeepCopy(), &pod, killSwitch)
+	r := &MonkeyReconciler{
+		Client:     c,
+		Scheme:     fakeScheme,
+		KillSwitch: killSwitchKey,
+	}
+
+	result, err := r.PerformExperiment(ctx, monkey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.RequeueAfter).Should(BeZero())
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(&pod), &corev1.Pod{})).To(Succeed())
+
+	stored := &podchaosv1alpha1.Monkey{}
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(monkey), stored)).To(Succeed())
+	suspended := meta.FindStatusCondition(stored.Status.Conditions, podchaosv1alpha1.ConditionSuspended)
+	g.Expect(suspended).ShouldNot(BeNil())
+	g.Expect(suspended.Status).Should(Equal(metav1.ConditionTrue))
+	g.Expect(suspended.Reason).Should(Equal(podchaosv1alpha1.ReasonKillSwitch))
+
+	// releasing the kill switch plans the next experiment from now rather than running the missed one straight away
+	killSwitch.Data[KillSwitchKey] = "false"
+	g.Expect(r.Update(ctx, killSwitch)).To(Succeed())
+	result, err = r.PerformExperiment(ctx, stored)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.RequeueAfter).Should(BeNumerically("~", 5*time.Minute, time.Second))
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(&pod), &corev1.Pod{})).To(Succeed())
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(monkey), stored)).To(Succeed())
+	g.Expect(meta.IsStatusConditionTrue(stored.Status.Conditions, podchaosv1alpha1.ConditionSuspended)).Should(BeFalse())
+	g.Expect(stored.Status.NextExperimentTime.Time).Should(BeTemporally("~", time.Now().Add(5*time.Minute), time.Second))
+	g.Expect(stored.Status.LastVictims).Should(BeEmpty())
+
+	// the experiment runs once the replanned time is due
+	stored.Status.NextExperimentTime = &metav1.Time{Time: time.Now().Add(-time.Second)}
+	g.Expect(r.Status().Update(ctx, stored)).To(Succeed())
+	_, err = r.PerformExperiment(ctx, stored)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(monkey), stored)).To(Succeed())
+	g.Expect(stored.Status.LastVictims).Should(HaveLen(1))
+}
+
+func TestMonkeyReconciler_mapKillSwitch(t *testing.T) {
+	g := NewWithT(t)
+	first := Monkey("first", "5m", "workloads", false, map[string]string{}, []metav1.Condition{})
+	second := Monkey("second", "5m", "other", false, map[string]string{}, []metav1.Condition{})
+	c, fakeScheme := InitTests(t, first, second)
+	r := &MonkeyReconciler{
+		Client:     c,
+		Scheme:     fakeScheme,
+		KillSwitch: killSwitchKey,
+	}
+
+	g.Expect(r.mapKillSwitch(KillSwitch("true"))).Should(ConsistOf(
+		reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "workloads", Name: "first"}},
+		reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "other", Name: "second"}},
+	))
+	other := KillSwitch("true")
+	other.Name = "unrelated"
+	g.Expect(r.mapKillSwitch(other)).Should(BeEmpty())
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	podchaosv1alpha1 "github.com/perithompson/podchaosmonkey/api/v1alpha1"
+)
+
+const (
+	failureReasonBlocked = "blocked"
+	failureReasonError   = "error"
+)
+
+var (
+	// PodsKilledTotal counts the pods deleted by each Monkey
+	PodsKilledTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "podchaosmonkey_pods_killed_total",
+		Help: "Total number of pods deleted by a Monkey",
+	}, []string{"monkey", "namespace"})
+
+	// NoopKillsTotal counts the pods each Monkey would have deleted if noop was not set
+	NoopKillsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "podchaosmonkey_noop_kills_total",
+		Help: "Total number of pods a Monkey would have deleted if noop was not set",
+	}, []string{"monkey", "namespace"})
+
+	// KillFailuresTotal counts the pods each Monkey failed to delete, including evictions blocked by a PodDisruptionBudget
+	KillFailuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "podchaosmonkey_kill_failures_total",
+		Help: "Total number of pods a Monkey failed to delete",
+	}, []string{"monkey", "namespace", "reason"})
+
+	// killFailureReasons are the values of the reason label of KillFailuresTotal
+	killFailureReasons = []string{failureReasonBlocked, failureReasonError}
+
+	// NoTargetRunsTotal counts the experiments of each Monkey that found no pods to delete
+	NoTargetRunsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "podchaosmonkey_no_target_runs_total",
+		Help: "Total number of experiments of a Monkey that found no pods to delete",
+	}, []string{"monkey", "namespace"})
+
+	// RecoveryChecksTotal counts the completed recovery checks of each Monkey by result
+	RecoveryChecksTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "podchaosmonkey_recovery_checks_total",
+		Help: "Total number of completed recovery checks of a Monkey",
+	}, []string{"monkey", "namespace", "result"})
+
+	// recoveryResults are the values of the result label of RecoveryChecksTotal
+	recoveryResults = []string{string(podchaosv1alpha1.RecoveryPassed), string(podchaosv1alpha1.RecoveryFailed)}
+
+	// TimeToRecoverySeconds observes how long workloads take to recover after a Monkey deleted one of their pods
+	TimeToRecoverySeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "podchaosmonkey_time_to_recovery_seconds",
+		Help:    "Time taken by a workload to recover after a Monkey deleted one of its pods",
+		Buckets: prometheus.ExponentialBuckets(5, 2, 8),
+	}, []string{"monkey", "namespace"})
+
+	// ReconcileDuration observes how long each reconcile of a Monkey takes
+	ReconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "podchaosmonkey_reconcile_duration_seconds",
+		Help:    "Time taken to reconcile a Monkey",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"monkey", "namespace"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		PodsKilledTotal,
+		NoopKillsTotal,
+		KillFailuresTotal,
+		NoTargetRunsTotal,
+		RecoveryChecksTotal,
+		TimeToRecoverySeconds,
+		ReconcileDuration,
+	)
+}
+
+//DeleteMonkeyMetrics removes the metrics of a Monkey that no longer exists
+func DeleteMonkeyMetrics(name, namespace string) {
+	labels := prometheus.Labels{"monkey": name, "namespace": namespace}
+	PodsKilledTotal.Delete(labels)
+	NoopKillsTotal.Delete(labels)
+	for _, reason := range killFailureReasons {
+		KillFailuresTotal.DeleteLabelValues(name, namespace, reason)
+	}
+	NoTargetRunsTotal.Delete(labels)
+	for _, result := range recoveryResults {
+		RecoveryChecksTotal.DeleteLabelValues(name, namespace, result)
+	}
+	TimeToRecoverySeconds.Delete(labels)
+	ReconcileDuration.Delete(labels)
+}
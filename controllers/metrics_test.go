@@ -0,0 +1,66 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	podchaosv1alpha1 "github.com/perithompson/podchaosmonkey/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestMonkeyReconciler_Metrics(t *testing.T) {
+	ctx := context.Background()
+	g := NewWithT(t)
+	killer := Monkey("metrics-killer", "5m", "metrics", false, map[string]string{"allowChaos": "true"}, []metav1.Condition{{Type: podchaosv1alpha1.ConditionReady, Status: metav1.ConditionTrue, Reason: podchaosv1alpha1.ReasonReconciled}})
+	killer.Spec.Mode = "all"
+	noop := Monkey("metrics-noop", "5m", "metrics", true, map[string]string{"allowChaos": "true"}, []metav1.Condition{{Type: podchaosv1alpha1.ConditionReady, Status: metav1.ConditionTrue, Reason: podchaosv1alpha1.ReasonReconciled}})
+	idle := Monkey("metrics-idle", "5m", "metrics", false, map[string]string{"allowChaos": "never"}, []metav1.Condition{{Type: podchaosv1alpha1.ConditionReady, Status: metav1.ConditionTrue, Reason: podchaosv1alpha1.ReasonReconciled}})
+	for _, monkey := range []*podchaosv1alpha1.Monkey{killer, noop, idle} {
+		monkey.Status.StartTime = &metav1.Time{Time: time.Now()}
+		// the metrics are registered globally, so start from zero however many times the test runs
+		DeleteMonkeyMetrics(monkey.Name, monkey.Namespace)
+	}
+	first := Pod("first", "1", "metrics", "true")
+	second := Pod("second", "2", "metrics", "true")
+	c, fakeScheme := InitTests(t, noop, idle, killer, &first, &second)
+	r := &MonkeyReconciler{
+		Client: c,
+		Scheme: fakeScheme,
+	}
+
+	for _, monkey := range []string{"metrics-noop", "metrics-idle", "metrics-killer"} {
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: monkey, Namespace: "metrics"}})
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+
+	g.Expect(testutil.ToFloat64(PodsKilledTotal.WithLabelValues("metrics-killer", "metrics"))).Should(Equal(float64(2)))
+	g.Expect(testutil.ToFloat64(NoopKillsTotal.WithLabelValues("metrics-noop", "metrics"))).Should(Equal(float64(1)))
+	g.Expect(testutil.ToFloat64(NoTargetRunsTotal.WithLabelValues("metrics-idle", "metrics"))).Should(Equal(float64(1)))
+	g.Expect(testutil.CollectAndCount(ReconcileDuration, "podchaosmonkey_reconcile_duration_seconds")).Should(BeNumerically(">=", 3))
+
+	g.Expect(c.Delete(ctx, killer)).To(Succeed())
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "metrics-killer", Namespace: "metrics"}})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(testutil.ToFloat64(PodsKilledTotal.WithLabelValues("metrics-killer", "metrics"))).Should(Equal(float64(0)))
+}
@@ -19,29 +19,62 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"math"
 	"math/rand"
+	"strings"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	podchaosv1alpha1 "github.com/perithompson/podchaosmonkey/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 )
 
 // MonkeyReconciler reconciles a Monkey object
 type MonkeyReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// Clientset is used for requests the controller-runtime client does not support, such as pod evictions
+	Clientset kubernetes.Interface
+
+	// Recorder emits Events on Monkeys and the owners of the pods they delete
+	Recorder record.EventRecorder
+
+	// ProtectedNamespaces are never touched by an experiment, regardless of the Monkey spec
+	ProtectedNamespaces []string
+
+	// WatchNamespaces are the only namespaces the controller watches when set, Monkeys targeting any other namespace are refused
+	WatchNamespaces []string
+
+	// KillSwitch is the ConfigMap that suspends every Monkey while its suspend key is "true"
+	KillSwitch types.NamespacedName
+
+	// APIReader reads Monkeys from the API server rather than the cache before their status is written, so a write never
+	// starts from a Monkey older than the controller's previous write. The Client is used when it is not set
+	APIReader client.Reader
 }
 
 //+kubebuilder:rbac:groups=podchaos.podchaosmonkey.pt,resources=monkeys,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=podchaos.podchaosmonkey.pt,resources=monkeys/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=podchaos.podchaosmonkey.pt,resources=monkeys/finalizers,verbs=update
 //+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;delete;watch;
+//+kubebuilder:rbac:groups=core,resources=pods/eviction,verbs=create
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -56,45 +89,250 @@ func (r *MonkeyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	if err := r.Get(ctx, req.NamespacedName, monkey); err != nil {
 		if !apierrors.IsNotFound(err) {
 			monkeySay.Error(err, fmt.Sprintf("Unable to fetch monkey: %v", req.NamespacedName))
+		} else {
+			DeleteMonkeyMetrics(req.Name, req.Namespace)
 		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+	timer := prometheus.NewTimer(ReconcileDuration.WithLabelValues(req.Name, req.Namespace))
+	defer timer.ObserveDuration()
 
-	if len(monkey.Status.Conditions) == 0 {
-		registeredCondition := metav1.Condition{
-			Type:               "Registered",
-			Status:             metav1.ConditionTrue,
-			ObservedGeneration: 0,
-			LastTransitionTime: metav1.Now(),
-			Reason:             "Registered",
-			Message:            "",
+	if invalid, err := r.CheckSpec(ctx, monkey); invalid || err != nil {
+		return ctrl.Result{}, err
+	}
+	if monkey.Status.StartTime == nil {
+		next, err := GetNextExperimentTime(monkey.Spec, time.Now())
+		if err != nil {
+			return ctrl.Result{}, err
 		}
-		monkey.Status.Conditions = append(monkey.Status.Conditions, registeredCondition)
+		monkey.Status.NextExperimentTime = &metav1.Time{Time: next}
+		monkey.Status.StartTime = &metav1.Time{Time: time.Now()}
+		monkey.Status.ObservedGeneration = monkey.GetGeneration()
 		return r.UpdateStatus(ctx, monkey)
 	}
-	return r.PerformExperiment(ctx, monkey)
+	if _, err := r.ResumeIfRequested(ctx, monkey); err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(monkey.Status.PendingRecoveries) > 0 {
+		changed, err := r.CheckRecoveries(ctx, monkey, time.Now())
+		if err != nil {
+			return ctrl.Result{RequeueAfter: RecoveryPollInterval}, err
+		}
+		if changed {
+			if _, err := r.UpdateStatus(ctx, monkey); err != nil {
+				return ctrl.Result{RequeueAfter: RecoveryPollInterval}, err
+			}
+		}
+	}
+	result, err := r.PerformExperiment(ctx, monkey)
+	if len(monkey.Status.PendingRecoveries) > 0 && (result.RequeueAfter == 0 || result.RequeueAfter > RecoveryPollInterval) {
+		result.RequeueAfter = RecoveryPollInterval
+	}
+	return result, err
 }
 
-//GetTarget chooses 1 pod that matches the namespace and labelselector provided to be deleted
-func (r *MonkeyReconciler) GetTarget(ctx context.Context, namespace string, labelSelector metav1.LabelSelector) (corev1.Pod, error) {
-	var list corev1.PodList
+//GetTargetNamespaces returns the namespaces a monkey is allowed to search for pods, defaulting to the monkey's own namespace
+func (r *MonkeyReconciler) GetTargetNamespaces(ctx context.Context, monkey *podchaosv1alpha1.Monkey) ([]string, error) {
+	var namespaces []string
+	seen := map[string]bool{}
+	add := func(namespace string) {
+		if namespace != "" && !seen[namespace] {
+			seen[namespace] = true
+			namespaces = append(namespaces, namespace)
+		}
+	}
 
-	rand.Seed(time.Now().UnixNano())
+	add(monkey.Spec.Namespace)
+	for _, namespace := range monkey.Spec.Namespaces {
+		add(namespace)
+	}
+	if monkey.Spec.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(monkey.Spec.NamespaceSelector)
+		if err != nil {
+			return nil, err
+		}
+		var list corev1.NamespaceList
+		if err := r.List(ctx, &list, &client.ListOptions{LabelSelector: selector}); err != nil {
+			return nil, err
+		}
+		for _, namespace := range list.Items {
+			add(namespace.GetName())
+		}
+		return namespaces, nil
+	}
+	if len(namespaces) == 0 {
+		add(monkey.GetNamespace())
+	}
+	return namespaces, nil
+}
+
+//GetProtectedNamespaces returns the namespaces from the list provided that the controller must never touch
+func (r *MonkeyReconciler) GetProtectedNamespaces(namespaces []string) []string {
+	var protected []string
+	for _, namespace := range namespaces {
+		for _, protectedNamespace := range r.ProtectedNamespaces {
+			if namespace == protectedNamespace {
+				protected = append(protected, namespace)
+				break
+			}
+		}
+	}
+	return protected
+}
+
+//GetOutOfScopeNamespaces returns the namespaces from the list provided that the controller does not watch
+func (r *MonkeyReconciler) GetOutOfScopeNamespaces(namespaces []string) []string {
+	if len(r.WatchNamespaces) == 0 {
+		return nil
+	}
+	var outOfScope []string
+	for _, namespace := range namespaces {
+		watched := false
+		for _, watchNamespace := range r.WatchNamespaces {
+			if namespace == watchNamespace {
+				watched = true
+				break
+			}
+		}
+		if !watched {
+			outOfScope = append(outOfScope, namespace)
+		}
+	}
+	return outOfScope
+}
+
+//refuse sets the Refused condition with the reason and message provided and requeues the monkey
+func (r *MonkeyReconciler) refuse(ctx context.Context, monkey *podchaosv1alpha1.Monkey, reason, message string, requeueInterval time.Duration) (ctrl.Result, error) {
+	ctrl.Log.WithName("controller").WithName("monkey").Info(message)
+	r.recordEvent(monkey, corev1.EventTypeWarning, EventReasonRefused, message)
+	setCondition(monkey, podchaosv1alpha1.ConditionRefused, metav1.ConditionTrue, reason, message)
+	return r.updateStatusAndRequeue(ctx, monkey, requeueInterval)
+}
+
+//GetCandidates lists the pods that match the namespaces and labelselector provided
+func (r *MonkeyReconciler) GetCandidates(ctx context.Context, namespaces []string, labelSelector metav1.LabelSelector) ([]corev1.Pod, error) {
+	var candidates []corev1.Pod
 
 	selector, err := metav1.LabelSelectorAsSelector(&labelSelector)
 	if err != nil {
-		return corev1.Pod{}, err
+		return nil, err
 	}
 
-	if err := r.List(ctx, &list, &client.ListOptions{LabelSelector: selector}); err != nil {
-		return corev1.Pod{}, err
+	for _, namespace := range namespaces {
+		var list corev1.PodList
+		if err := r.List(ctx, &list, &client.ListOptions{Namespace: namespace, LabelSelector: selector}); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, list.Items...)
 	}
-	if len(list.Items) > 0 {
-		max := len(list.Items)
-		randomID := rand.Intn(max)
-		return list.Items[randomID], nil
+	return candidates, nil
+}
+
+//IsEligible returns true when the pod provided may be deleted according to the candidate policy provided. Without a
+//policy only ready, running pods that are not already being deleted are eligible
+func IsEligible(pod *corev1.Pod, policy *podchaosv1alpha1.CandidatePolicy) bool {
+	if policy == nil {
+		policy = &podchaosv1alpha1.CandidatePolicy{}
+	}
+	if pod.GetDeletionTimestamp() != nil && !policy.IncludeTerminating {
+		return false
+	}
+	phases := policy.Phases
+	if len(phases) == 0 {
+		phases = []corev1.PodPhase{corev1.PodRunning}
+	}
+	for _, phase := range phases {
+		if pod.Status.Phase == phase {
+			return policy.IncludeUnready || IsPodReady(pod)
+		}
+	}
+	return false
+}
+
+//IsExcluded returns true when the pod provided opts out of Chaos with the exclude annotation or matches the exclude
+//selector provided
+func IsExcluded(pod *corev1.Pod, exclude labels.Selector) bool {
+	if pod.GetAnnotations()[podchaosv1alpha1.ExcludeAnnotation] == "true" {
+		return true
+	}
+	return exclude != nil && exclude.Matches(labels.Set(pod.GetLabels()))
+}
+
+//FilterCandidates returns the pods provided that are eligible to be deleted according to the candidate policy provided
+//and are not excluded
+func FilterCandidates(pods []corev1.Pod, policy *podchaosv1alpha1.CandidatePolicy, exclude labels.Selector) []corev1.Pod {
+	var eligible []corev1.Pod
+	for i := range pods {
+		if IsEligible(&pods[i], policy) && !IsExcluded(&pods[i], exclude) {
+			eligible = append(eligible, pods[i])
+		}
+	}
+	return eligible
+}
+
+//GetKillCount returns how many of the matching pods the mode and value provided should delete
+func GetKillCount(mode podchaosv1alpha1.KillMode, value int32, matching int) (int, error) {
+	count := 0
+	switch mode {
+	case "", podchaosv1alpha1.KillModeOne:
+		count = 1
+	case podchaosv1alpha1.KillModeFixed:
+		if value < 1 {
+			return 0, fmt.Errorf("mode %s requires a value of at least 1", mode)
+		}
+		count = int(value)
+	case podchaosv1alpha1.KillModePercent:
+		if value < 1 || value > 100 {
+			return 0, fmt.Errorf("mode %s requires a value between 1 and 100", mode)
+		}
+		// Always delete at least 1 pod so small percentages of small workloads are not a no-op
+		count = int(math.Max(1, math.Floor(float64(matching)*float64(value)/100)))
+	case podchaosv1alpha1.KillModeAll:
+		count = matching
+	default:
+		return 0, fmt.Errorf("unknown mode %q", mode)
+	}
+	if count > matching {
+		count = matching
+	}
+	return count, nil
+}
+
+//GetTargets chooses pods that match the namespaces and labelselector provided, do not match the exclude selector
+//provided and are eligible according to the candidate policy provided to be deleted, at random without replacement
+func (r *MonkeyReconciler) GetTargets(ctx context.Context, namespaces []string, labelSelector metav1.LabelSelector, excludeSelector *metav1.LabelSelector, policy *podchaosv1alpha1.CandidatePolicy, mode podchaosv1alpha1.KillMode, value int32) ([]corev1.Pod, error) {
+	rand.Seed(time.Now().UnixNano())
+
+	var exclude labels.Selector
+	if excludeSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(excludeSelector)
+		if err != nil {
+			return nil, err
+		}
+		exclude = selector
+	}
+	matching, err := r.GetCandidates(ctx, namespaces, labelSelector)
+	if err != nil {
+		return nil, err
+	}
+	candidates := FilterCandidates(matching, policy, exclude)
+	count, err := GetKillCount(mode, value, len(candidates))
+	if err != nil {
+		return nil, err
+	}
+	rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	return candidates[:count], nil
+}
+
+//GetTarget chooses 1 ready, running pod that matches the namespaces and labelselector provided to be deleted
+func (r *MonkeyReconciler) GetTarget(ctx context.Context, namespaces []string, labelSelector metav1.LabelSelector) (corev1.Pod, error) {
+	targets, err := r.GetTargets(ctx, namespaces, labelSelector, nil, nil, podchaosv1alpha1.KillModeOne, 0)
+	if err != nil || len(targets) == 0 {
+		return corev1.Pod{}, err
 	}
-	return corev1.Pod{}, nil
+	return targets[0], nil
 }
 
 //int64ToPointerint64 returns pointer of int64
@@ -102,57 +340,315 @@ func int64ToPointerint64(in int64) *int64 {
 	return &in
 }
 
-//PerformExperiment deletes 1 pod that matches the namespace and labelselector provided
+//GetGracePeriodSeconds returns the grace period to delete pods with, nil meaning the grace period of each pod
+func GetGracePeriodSeconds(spec podchaosv1alpha1.MonkeySpec) *int64 {
+	if spec.GracePeriodSeconds == nil {
+		return int64ToPointerint64(podchaosv1alpha1.DefaultGracePeriodSeconds)
+	}
+	if *spec.GracePeriodSeconds == podchaosv1alpha1.UsePodGracePeriod {
+		return nil
+	}
+	return int64ToPointerint64(*spec.GracePeriodSeconds)
+}
+
+//KillPod deletes the pod provided, or evicts it when the monkey honors PodDisruptionBudgets
+func (r *MonkeyReconciler) KillPod(ctx context.Context, monkey *podchaosv1alpha1.Monkey, pod *corev1.Pod) error {
+	gracePeriodSeconds := GetGracePeriodSeconds(monkey.Spec)
+	if !monkey.Spec.UseEviction {
+		return r.Delete(ctx, pod, &client.DeleteOptions{GracePeriodSeconds: gracePeriodSeconds})
+	}
+	if r.Clientset == nil {
+		return fmt.Errorf("unable to evict pod %s: no clientset configured", client.ObjectKeyFromObject(pod))
+	}
+	return r.Clientset.PolicyV1().Evictions(pod.GetNamespace()).Evict(ctx, &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.GetName(),
+			Namespace: pod.GetNamespace(),
+		},
+		DeleteOptions: &metav1.DeleteOptions{GracePeriodSeconds: gracePeriodSeconds},
+	})
+}
+
+//PerformExperiment deletes the pods chosen by the monkey's mode from those matching the namespaces and labelselector provided once the monkey is due to run
 func (r *MonkeyReconciler) PerformExperiment(ctx context.Context, monkey *podchaosv1alpha1.Monkey) (ctrl.Result, error) {
 	monkeySay := ctrl.Log.WithName("controller").WithName("monkey")
-	target, err := r.GetTarget(ctx, monkey.Spec.Namespace, monkey.Spec.Selector)
+	now := time.Now()
+	replanned, err := ReplanExperiment(monkey, now)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	requeueInterval, err := GetMinInterval(monkey.Spec.Interval)
+	if replanned {
+		if _, err := r.UpdateStatus(ctx, monkey); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if IsHalted(monkey) {
+		return ctrl.Result{}, nil
+	}
+	completed, err := r.CheckCompletion(monkey, now)
 	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if completed {
+		_, err := r.UpdateStatus(ctx, monkey)
+		return ctrl.Result{}, err
+	}
+	suspended, err := r.CheckKillSwitch(ctx, monkey, now)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if suspended {
+		return ctrl.Result{}, nil
+	}
+	if monkey.Spec.Suspend {
+		return r.Suspend(ctx, monkey)
+	}
+	resumed, err := r.ResumeFromSuspend(ctx, monkey, now)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if resumed {
+		return ctrl.Result{RequeueAfter: monkey.Status.NextExperimentTime.Sub(now)}, nil
+	}
+	if next := monkey.Status.NextExperimentTime; next != nil && now.Before(next.Time) {
+		return ctrl.Result{RequeueAfter: next.Sub(now)}, nil
+	}
+	missed := monkey.Status.NextExperimentTime
+	skipped, err := SkipMissedExperiment(monkey, now)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if skipped {
+		monkeySay.Info(fmt.Sprintf("Skipping experiment of monkey %s missed at %s, next experiment at %s", client.ObjectKeyFromObject(monkey), missed.Format(time.RFC3339), monkey.Status.NextExperimentTime.Format(time.RFC3339)))
+		if _, err := r.UpdateStatus(ctx, monkey); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: monkey.Status.NextExperimentTime.Sub(now)}, nil
+	}
+	requeueInterval, err := GetRequeueInterval(monkey, now)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: requeueInterval}, err
+	}
+	monkey.Status.NextExperimentTime = &metav1.Time{Time: now.Add(requeueInterval)}
+	if end, ok, err := GetEndTime(monkey); err == nil && ok && end.Sub(now) < requeueInterval {
+		// Requeue when the duration elapses so the monkey completes on time
+		requeueInterval = end.Sub(now)
+	}
+	claimed, err := r.claimExperiment(ctx, monkey, now)
+	if err != nil || !claimed {
 		return ctrl.Result{RequeueAfter: requeueInterval}, err
 	}
-	if target.GetUID() != "" {
-		podname := client.ObjectKeyFromObject(&target)
+
+	window, windowEnd, err := GetActiveBlackoutWindow(monkey.Spec, now)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: requeueInterval}, err
+	}
+	if window != nil {
+		message := fmt.Sprintf("Chaos suspended by blackout window %q until %s", window.Name, windowEnd.UTC().Format(time.RFC3339))
+		if setCondition(monkey, podchaosv1alpha1.ConditionSuspended, metav1.ConditionTrue, podchaosv1alpha1.ReasonBlackoutWindow, message) {
+			monkeySay.Info(message)
+			r.recordEvent(monkey, corev1.EventTypeNormal, EventReasonSuspended, message)
+		}
+		return r.updateStatusAndRequeue(ctx, monkey, requeueInterval)
+	}
+	clearCondition(monkey, podchaosv1alpha1.ConditionSuspended, podchaosv1alpha1.ReasonActive)
+
+	// Namespaces are cluster scoped so they cannot be listed when the controller only watches some namespaces
+	if len(r.WatchNamespaces) > 0 && monkey.Spec.NamespaceSelector != nil {
+		message := "Refusing to select namespaces by label as the controller only watches namespaces: " + strings.Join(r.WatchNamespaces, ", ")
+		return r.refuse(ctx, monkey, podchaosv1alpha1.ReasonNamespaceOutOfScope, message, requeueInterval)
+	}
+	namespaces, err := r.GetTargetNamespaces(ctx, monkey)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if protected := r.GetProtectedNamespaces(namespaces); len(protected) > 0 {
+		message := fmt.Sprintf("Refusing to delete pods in protected namespaces: %s", strings.Join(protected, ", "))
+		return r.refuse(ctx, monkey, podchaosv1alpha1.ReasonProtectedNamespace, message, requeueInterval)
+	}
+	if outOfScope := r.GetOutOfScopeNamespaces(namespaces); len(outOfScope) > 0 {
+		message := fmt.Sprintf("Refusing to delete pods in namespaces the controller does not watch: %s", strings.Join(outOfScope, ", "))
+		return r.refuse(ctx, monkey, podchaosv1alpha1.ReasonNamespaceOutOfScope, message, requeueInterval)
+	}
+	clearCondition(monkey, podchaosv1alpha1.ConditionRefused, podchaosv1alpha1.ReasonAllowed)
+
+	halted, err := r.CheckAbortPolicy(ctx, monkey, namespaces)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if halted {
+		_, err := r.UpdateStatus(ctx, monkey)
+		return ctrl.Result{}, err
+	}
+
+	recoveryTimeout, err := GetRecoveryTimeout(monkey.Spec)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	targets, err := r.GetTargets(ctx, namespaces, monkey.Spec.Selector, monkey.Spec.ExcludeSelector, monkey.Spec.Candidates, monkey.Spec.Mode, monkey.Spec.Value)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if remaining, ok := GetRemainingKills(monkey); ok && !monkey.Spec.Noop && int64(len(targets)) > remaining {
+		targets = targets[:remaining]
+	}
+	if len(targets) == 0 {
+		NoTargetRunsTotal.WithLabelValues(monkey.GetName(), monkey.GetNamespace()).Inc()
+		r.recordEvent(monkey, corev1.EventTypeNormal, EventReasonNoTarget, "No eligible pods matching the selector found in namespaces: %s", strings.Join(namespaces, ", "))
+	}
+	unhealthy, err := r.GetUnhealthyTargets(ctx, targets)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(unhealthy) > 0 {
+		message := fmt.Sprintf("Skipping experiment as targets are not in a steady state: %s", strings.Join(unhealthy, ", "))
+		monkeySay.Info(message)
+		r.recordEvent(monkey, corev1.EventTypeWarning, EventReasonTargetUnhealthy, message)
+		setCondition(monkey, podchaosv1alpha1.ConditionTargetUnhealthy, metav1.ConditionTrue, podchaosv1alpha1.ReasonWorkloadUnhealthy, message)
+		for i := range targets {
+			entry := NewExperimentRecord(monkey, &targets[i], now)
+			entry.Result = podchaosv1alpha1.ResultSkipped
+			entry.Message = "Target unhealthy"
+			RecordExperiment(monkey, entry)
+		}
+		return r.updateStatusAndRequeue(ctx, monkey, requeueInterval)
+	}
+	if len(targets) > 0 {
+		clearCondition(monkey, podchaosv1alpha1.ConditionTargetUnhealthy, podchaosv1alpha1.ReasonHealthy)
+	}
+	var victims []podchaosv1alpha1.Victim
+	var blocked []string
+	var deleteErr error
+	for i := range targets {
+		target := &targets[i]
+		podname := client.ObjectKeyFromObject(target)
+		entry := NewExperimentRecord(monkey, target, now)
 		if monkey.Spec.Noop {
 			monkeySay.Info(fmt.Sprintf("No Operation specified ==== Would have deleted pod: %s", podname))
-			return ctrl.Result{RequeueAfter: requeueInterval}, nil
+			r.recordEvent(monkey, corev1.EventTypeNormal, EventReasonNoopKill, "Would have deleted pod %s", podname)
+			NoopKillsTotal.WithLabelValues(monkey.GetName(), monkey.GetNamespace()).Inc()
+			entry.Result = podchaosv1alpha1.ResultSkipped
+			RecordExperiment(monkey, entry)
+			continue
+		}
+		err := r.KillPod(ctx, monkey, target)
+		switch {
+		case err == nil:
+			gracePeriodSeconds := GetGracePeriodSeconds(monkey.Spec)
+			if gracePeriodSeconds == nil {
+				gracePeriodSeconds = target.Spec.TerminationGracePeriodSeconds
+			}
+			monkeySay.Info(fmt.Sprintf("Deleted Pod: %s", podname))
+			r.recordEvent(monkey, corev1.EventTypeNormal, EventReasonPodKilled, "Deleted pod %s", podname)
+			r.recordOwnerEvent(target, corev1.EventTypeNormal, EventReasonPodKilled, "Pod %s was deleted by Monkey %s", podname, client.ObjectKeyFromObject(monkey))
+			PodsKilledTotal.WithLabelValues(monkey.GetName(), monkey.GetNamespace()).Inc()
+			entry.Result = podchaosv1alpha1.ResultSucceeded
+			entry.GracePeriodSeconds = gracePeriodSeconds
+			victims = append(victims, podchaosv1alpha1.Victim{
+				Namespace:          target.GetNamespace(),
+				Name:               target.GetName(),
+				UID:                target.GetUID(),
+				GracePeriodSeconds: gracePeriodSeconds,
+			})
+			if recoveryTimeout > 0 {
+				if check := NewRecoveryCheck(target, now, recoveryTimeout); check != nil {
+					monkey.Status.PendingRecoveries = append(monkey.Status.PendingRecoveries, *check)
+				}
+			}
+		case apierrors.IsNotFound(err):
+			entry.Result = podchaosv1alpha1.ResultSkipped
+			entry.Message = "Pod no longer exists"
+		// The eviction API responds with 429 Too Many Requests when a PodDisruptionBudget does not allow the disruption
+		case monkey.Spec.UseEviction && apierrors.IsTooManyRequests(err):
+			monkeySay.Info(fmt.Sprintf("Eviction blocked by PodDisruptionBudget: %s", podname))
+			r.recordEvent(monkey, corev1.EventTypeWarning, EventReasonEvictionBlocked, "Eviction of pod %s blocked by PodDisruptionBudget", podname)
+			KillFailuresTotal.WithLabelValues(monkey.GetName(), monkey.GetNamespace(), failureReasonBlocked).Inc()
+			entry.Result = podchaosv1alpha1.ResultBlocked
+			entry.Message = err.Error()
+			blocked = append(blocked, podname.String())
+		default:
+			r.recordEvent(monkey, corev1.EventTypeWarning, EventReasonKillFailed, "Failed to delete pod %s: %v", podname, err)
+			KillFailuresTotal.WithLabelValues(monkey.GetName(), monkey.GetNamespace(), failureReasonError).Inc()
+			entry.Result = podchaosv1alpha1.ResultFailed
+			entry.Message = err.Error()
+			deleteErr = err
 		}
-		if err := r.Delete(ctx, &target, &client.DeleteOptions{GracePeriodSeconds: int64ToPointerint64(0)}); err != nil {
-			return ctrl.Result{RequeueAfter: requeueInterval}, err
+		RecordExperiment(monkey, entry)
+		if deleteErr != nil {
+			break
 		}
-		monkeySay.Info(fmt.Sprintf("Deleted Pod: %s", podname))
+	}
+	if len(victims) > 0 {
+		monkey.Status.LastVictims = victims
+	}
+	if len(blocked) > 0 {
+		message := fmt.Sprintf("Eviction blocked by PodDisruptionBudget for pods: %s", strings.Join(blocked, ", "))
+		setCondition(monkey, podchaosv1alpha1.ConditionEvictionBlocked, metav1.ConditionTrue, podchaosv1alpha1.ReasonPodDisruptionBudget, message)
+	} else if len(victims) > 0 {
+		clearCondition(monkey, podchaosv1alpha1.ConditionEvictionBlocked, podchaosv1alpha1.ReasonEvicted)
+	}
+	if completed, err := r.CheckCompletion(monkey, now); err == nil && completed {
+		requeueInterval = 0
+	}
+	result, err := r.updateStatusAndRequeue(ctx, monkey, requeueInterval)
+	if deleteErr != nil {
+		return result, deleteErr
+	}
+	return result, err
+}
+
+//updateStatusAndRequeue updates the status of the monkey and requeues it after the interval provided
+func (r *MonkeyReconciler) updateStatusAndRequeue(ctx context.Context, monkey *podchaosv1alpha1.Monkey, requeueInterval time.Duration) (ctrl.Result, error) {
+	if _, err := r.UpdateStatus(ctx, monkey); err != nil {
+		return ctrl.Result{RequeueAfter: requeueInterval}, err
 	}
 	return ctrl.Result{RequeueAfter: requeueInterval}, nil
 }
 
+//reader returns the reader used to fetch a Monkey before its status is written
+func (r *MonkeyReconciler) reader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	return r.Client
+}
+
 //UpdateStatus updates the status of the Monkey Object
 func (r *MonkeyReconciler) UpdateStatus(ctx context.Context, monkey *podchaosv1alpha1.Monkey) (ctrl.Result, error) {
 	monkeySay := ctrl.Log.WithName("controller").WithName("monkey")
 	newKey := client.ObjectKeyFromObject(monkey)
-	newObject := &podchaosv1alpha1.Monkey{}
-	if err := r.Get(ctx, newKey, newObject); err != nil {
-		if !apierrors.IsNotFound(err) {
-			monkeySay.Error(err, fmt.Sprintf("Unable to fetch monkey: %v", newKey))
+	SetSummaryConditions(monkey)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		newObject := &podchaosv1alpha1.Monkey{}
+		if err := r.reader().Get(ctx, newKey, newObject); err != nil {
+			return err
 		}
-		return ctrl.Result{}, client.IgnoreNotFound(err)
-	}
-	newObject.Status = monkey.Status
-	if err := r.Status().Update(ctx, newObject); err != nil {
-		monkeySay.Error(err, fmt.Sprintf("Unable to update Monkey: %v", newObject.GetName()))
+		newObject.Status = monkey.Status
+		if err := r.Status().Update(ctx, newObject); err != nil {
+			return err
+		}
+		monkey.SetResourceVersion(newObject.GetResourceVersion())
+		return nil
+	})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		monkeySay.Error(err, fmt.Sprintf("Unable to update Monkey: %v", newKey))
+		return ctrl.Result{}, err
 	}
-	requeueInterval, err := GetMinInterval(monkey.Spec.Interval)
+	requeueInterval, err := GetRequeueInterval(monkey, time.Now())
 	if err != nil {
-		return ctrl.Result{RequeueAfter: requeueInterval}, err
+		// An invalid spec is reported by the InvalidSpec condition rather than retried
+		return ctrl.Result{}, nil
 	}
-	return ctrl.Result{RequeueAfter: requeueInterval}, err
+	return ctrl.Result{RequeueAfter: requeueInterval}, nil
 }
 
 //GetMinInterval Gets the minimal intervals for Chaos to occur
 func GetMinInterval(interval string) (time.Duration, error) {
 	if interval == "" {
-		return time.ParseDuration("30s")
+		return podchaosv1alpha1.DefaultInterval, nil
 	}
 	return time.ParseDuration(interval)
 }
@@ -161,5 +657,6 @@ func GetMinInterval(interval string) (time.Duration, error) {
 func (r *MonkeyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&podchaosv1alpha1.Monkey{}).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.mapKillSwitch)).
 		Complete(r)
 }
@@ -18,15 +18,26 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
+	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	podchaosv1alpha1 "github.com/perithompson/podchaosmonkey/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	fakeClient "sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -41,6 +52,7 @@ func InitTests(t *testing.T, initObjs ...client.Object) (client.WithWatch, *runt
 		Panic()
 	}
 	corev1.AddToScheme(fakeScheme)
+	appsv1.AddToScheme(fakeScheme)
 	if err != nil {
 		Panic()
 	}
@@ -231,13 +243,16 @@ func TestMonkeyReconciler_PerformExperiment(t *testing.T) {
 		monkey *podchaosv1alpha1.Monkey
 	}
 	tests := []struct {
-		name         string
-		fields       fields
-		args         args
-		pods         corev1.PodList
-		wantPodCount int
-		want         ctrl.Result
-		wantErr      bool
+		name                string
+		fields              fields
+		args                args
+		pods                corev1.PodList
+		protectedNamespaces []string
+		wantPodCount        int
+		wantRefused         bool
+		wantSuspended       bool
+		want                ctrl.Result
+		wantErr             bool
 	}{
 		{
 			name: "delete-1",
@@ -260,13 +275,68 @@ func TestMonkeyReconciler_PerformExperiment(t *testing.T) {
 			want:         ctrl.Result{RequeueAfter: time.Duration(5 * time.Minute)},
 			wantErr:      false,
 		},
+		{
+			name: "protected-namespace",
+			fields: fields{
+				Client: c,
+				Scheme: fakeScheme,
+			},
+			args: args{
+				ctx:    context.Background(),
+				monkey: Monkey("test", "5m", "kube-system", false, map[string]string{}, []metav1.Condition{}),
+			},
+			pods: corev1.PodList{
+				Items: []corev1.Pod{
+					Pod("coredns-1", "4", "kube-system", "true"),
+					Pod("coredns-2", "5", "kube-system", "true"),
+				},
+			},
+			protectedNamespaces: []string{"kube-system", "kube-public"},
+			wantPodCount:        4,
+			wantRefused:         true,
+			want:                ctrl.Result{RequeueAfter: time.Duration(5 * time.Minute)},
+			wantErr:             false,
+		},
+		{
+			name: "blackout-window",
+			fields: fields{
+				Client: c,
+				Scheme: fakeScheme,
+			},
+			args: args{
+				ctx: context.Background(),
+				monkey: func() *podchaosv1alpha1.Monkey {
+					m := Monkey("frozen", "5m", "frozen", false, map[string]string{}, []metav1.Condition{})
+					m.Spec.BlackoutWindows = []podchaosv1alpha1.BlackoutWindow{
+						{
+							Name:  "release-freeze",
+							Start: &metav1.Time{Time: time.Now().Add(-time.Hour)},
+							End:   &metav1.Time{Time: time.Now().Add(time.Hour)},
+						},
+					}
+					return m
+				}(),
+			},
+			pods: corev1.PodList{
+				Items: []corev1.Pod{
+					Pod("frozen-1", "6", "frozen", "true"),
+				},
+			},
+			wantPodCount:  5,
+			wantSuspended: true,
+			want:          ctrl.Result{RequeueAfter: time.Duration(5 * time.Minute)},
+			wantErr:       false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &MonkeyReconciler{
-				Client: tt.fields.Client,
-				Scheme: tt.fields.Scheme,
+				Client:              tt.fields.Client,
+				Scheme:              tt.fields.Scheme,
+				ProtectedNamespaces: tt.protectedNamespaces,
 			}
+			err := r.Create(tt.args.ctx, tt.args.monkey.DeepCopy())
+			g.Expect(err).ToNot(HaveOccurred())
 			for _, p := range tt.pods.Items {
 				err := r.Create(tt.args.ctx, &p)
 				g.Expect(err).ToNot(HaveOccurred())
@@ -281,11 +351,80 @@ func TestMonkeyReconciler_PerformExperiment(t *testing.T) {
 				err := r.List(tt.args.ctx, gotPodList)
 				g.Expect(err).ToNot(HaveOccurred())
 				g.Expect(len(gotPodList.Items)).Should(Equal(tt.wantPodCount))
+				gotMonkey := &podchaosv1alpha1.Monkey{}
+				err = r.Get(tt.args.ctx, client.ObjectKeyFromObject(tt.args.monkey), gotMonkey)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(meta.IsStatusConditionTrue(gotMonkey.Status.Conditions, podchaosv1alpha1.ConditionRefused)).Should(Equal(tt.wantRefused))
+				g.Expect(meta.IsStatusConditionTrue(gotMonkey.Status.Conditions, podchaosv1alpha1.ConditionSuspended)).Should(Equal(tt.wantSuspended))
 			}
 		})
 	}
 }
 
+func TestMonkeyReconciler_GetProtectedNamespaces(t *testing.T) {
+	g := NewWithT(t)
+	r := &MonkeyReconciler{
+		ProtectedNamespaces: []string{"kube-system", "kube-public", "podchaosmonkey-system"},
+	}
+	g.Expect(r.GetProtectedNamespaces([]string{"workloads", "team-a"})).Should(BeEmpty())
+	g.Expect(r.GetProtectedNamespaces([]string{"workloads", "kube-system", "podchaosmonkey-system"})).Should(Equal([]string{"kube-system", "podchaosmonkey-system"}))
+	g.Expect((&MonkeyReconciler{}).GetProtectedNamespaces([]string{"kube-system"})).Should(BeEmpty())
+}
+
+func TestMonkeyReconciler_GetOutOfScopeNamespaces(t *testing.T) {
+	g := NewWithT(t)
+	r := &MonkeyReconciler{
+		WatchNamespaces: []string{"workloads", "team-a"},
+	}
+	g.Expect(r.GetOutOfScopeNamespaces([]string{"workloads", "team-a"})).Should(BeEmpty())
+	g.Expect(r.GetOutOfScopeNamespaces([]string{"workloads", "team-b", "team-c"})).Should(Equal([]string{"team-b", "team-c"}))
+	g.Expect((&MonkeyReconciler{}).GetOutOfScopeNamespaces([]string{"team-b"})).Should(BeEmpty())
+}
+
+func TestMonkeyReconciler_PerformExperimentWatchNamespaces(t *testing.T) {
+	tests := []struct {
+		name              string
+		namespaces        []string
+		namespaceSelector *metav1.LabelSelector
+		wantRefused       bool
+	}{
+		{name: "watched", namespaces: []string{"workloads"}, wantRefused: false},
+		{name: "not watched", namespaces: []string{"workloads", "team-b"}, wantRefused: true},
+		{name: "namespace selector", namespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"chaos": "enabled"}}, wantRefused: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			g := NewWithT(t)
+			monkey := Monkey("scoped", "5m", "workloads", false, map[string]string{"allowChaos": "true"}, []metav1.Condition{})
+			monkey.Spec.Namespaces = tt.namespaces
+			monkey.Spec.NamespaceSelector = tt.namespaceSelector
+			pod := Pod("delete", "1", "workloads", "true")
+			c, fakeScheme := InitTests(t, monkey.DeepCopy(), &pod)
+			r := &MonkeyReconciler{
+				Client:          c,
+				Scheme:          fakeScheme,
+				WatchNamespaces: []string{"workloads"},
+			}
+
+			_, err := r.PerformExperiment(ctx, monkey)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			stored := &podchaosv1alpha1.Monkey{}
+			g.Expect(r.Get(ctx, client.ObjectKeyFromObject(monkey), stored)).To(Succeed())
+			refused := meta.FindStatusCondition(stored.Status.Conditions, podchaosv1alpha1.ConditionRefused)
+			if !tt.wantRefused {
+				g.Expect(refused).Should(BeNil())
+				g.Expect(stored.Status.LastVictims).Should(HaveLen(1))
+				return
+			}
+			g.Expect(refused.Status).Should(Equal(metav1.ConditionTrue))
+			g.Expect(refused.Reason).Should(Equal(podchaosv1alpha1.ReasonNamespaceOutOfScope))
+			g.Expect(stored.Status.LastVictims).Should(BeEmpty())
+		})
+	}
+}
+
 func Pod(name, uid, namespace, allowChaos string) corev1.Pod {
 	return corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -296,6 +435,10 @@ func Pod(name, uid, namespace, allowChaos string) corev1.Pod {
 				"allowChaos": allowChaos,
 			},
 		},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
 	}
 }
 
@@ -327,7 +470,7 @@ func TestMonkeyReconciler_GetTarget(t *testing.T) {
 	}
 	type args struct {
 		ctx           context.Context
-		namespace     string
+		namespaces    []string
 		labelSelector metav1.LabelSelector
 	}
 	tests := []struct {
@@ -345,8 +488,8 @@ func TestMonkeyReconciler_GetTarget(t *testing.T) {
 				Scheme: fakeScheme,
 			},
 			args: args{
-				ctx:       nil,
-				namespace: "workloads",
+				ctx:        nil,
+				namespaces: []string{"workloads"},
 				labelSelector: metav1.LabelSelector{
 					MatchLabels: map[string]string{
 						"allowChaos": "true",
@@ -369,6 +512,28 @@ func TestMonkeyReconciler_GetTarget(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "other namespaces ignored",
+			fields: fields{
+				Client: c,
+				Scheme: fakeScheme,
+			},
+			args: args{
+				ctx:        nil,
+				namespaces: []string{"scoped"},
+				labelSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"allowChaos": "true",
+					},
+				},
+			},
+			pods: []corev1.Pod{
+				Pod("scoped-delete", "4", "scoped", "true"),
+				Pod("unscoped-1", "5", "unscoped", "true"),
+				Pod("unscoped-2", "6", "unscoped", "true"),
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -380,14 +545,585 @@ func TestMonkeyReconciler_GetTarget(t *testing.T) {
 				err := r.Create(tt.args.ctx, &p)
 				g.Expect(err).ToNot(HaveOccurred())
 			}
-			got, err := r.GetTarget(tt.args.ctx, tt.args.namespace, tt.args.labelSelector)
+			for i := 0; i < 10; i++ {
+				got, err := r.GetTarget(tt.args.ctx, tt.args.namespaces, tt.args.labelSelector)
+				if tt.wantErr {
+					g.Expect(err).To(HaveOccurred())
+				} else {
+					g.Expect(err).ToNot(HaveOccurred())
+					g.Expect(got.GetUID()).ShouldNot(BeEmpty())
+					g.Expect(got.Labels).Should(Equal(tt.args.labelSelector.MatchLabels))
+					g.Expect(tt.args.namespaces).Should(ContainElement(got.GetNamespace()))
+				}
+			}
+		})
+	}
+}
+
+func TestMonkeyReconciler_GetTargetNamespaces(t *testing.T) {
+	c, fakeScheme := InitTests(t,
+		Namespace("team-a", map[string]string{"chaos": "enabled"}),
+		Namespace("team-b", map[string]string{"chaos": "enabled"}),
+		Namespace("team-c", map[string]string{"chaos": "disabled"}),
+	)
+	g := NewWithT(t)
+	tests := []struct {
+		name    string
+		monkey  *podchaosv1alpha1.Monkey
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "defaults to monkey namespace",
+			monkey: func() *podchaosv1alpha1.Monkey {
+				m := Monkey("test", "5m", "workloads", false, map[string]string{}, []metav1.Condition{})
+				m.Spec.Namespace = ""
+				return m
+			}(),
+			want: []string{"workloads"},
+		},
+		{
+			name: "namespace and namespaces",
+			monkey: func() *podchaosv1alpha1.Monkey {
+				m := Monkey("test", "5m", "workloads", false, map[string]string{}, []metav1.Condition{})
+				m.Spec.Namespaces = []string{"team-a", "workloads"}
+				return m
+			}(),
+			want: []string{"workloads", "team-a"},
+		},
+		{
+			name: "namespace selector",
+			monkey: func() *podchaosv1alpha1.Monkey {
+				m := Monkey("test", "5m", "", false, map[string]string{}, []metav1.Condition{})
+				m.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"chaos": "enabled"}}
+				return m
+			}(),
+			want: []string{"team-a", "team-b"},
+		},
+		{
+			name: "namespace selector matching nothing",
+			monkey: func() *podchaosv1alpha1.Monkey {
+				m := Monkey("test", "5m", "", false, map[string]string{}, []metav1.Condition{})
+				m.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"chaos": "unknown"}}
+				return m
+			}(),
+			want: nil,
+		},
+		{
+			name: "invalid namespace selector",
+			monkey: func() *podchaosv1alpha1.Monkey {
+				m := Monkey("test", "5m", "", false, map[string]string{}, []metav1.Condition{})
+				m.Spec.NamespaceSelector = &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "chaos", Operator: "Bogus"}}}
+				return m
+			}(),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &MonkeyReconciler{
+				Client: c,
+				Scheme: fakeScheme,
+			}
+			got, err := r.GetTargetNamespaces(context.Background(), tt.monkey)
 			if tt.wantErr {
 				g.Expect(err).To(HaveOccurred())
 			} else {
 				g.Expect(err).ToNot(HaveOccurred())
-				g.Expect(got.GetUID()).Should(Equal(got.GetUID()))
-				g.Expect(got.Labels).Should(Equal(tt.args.labelSelector.MatchLabels))
+				g.Expect(got).Should(Equal(tt.want))
+			}
+		})
+	}
+}
+
+func Namespace(name string, labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+	}
+}
+
+var _ = Describe("Monkey namespace scoping", func() {
+	ctx := context.Background()
+	selector := map[string]string{"allowChaos": "true"}
+
+	BeforeEach(func() {
+		for _, namespace := range []*corev1.Namespace{
+			Namespace("scope-in", map[string]string{"chaos": "enabled"}),
+			Namespace("scope-selected", map[string]string{"chaos": "enabled"}),
+			Namespace("scope-out", map[string]string{"chaos": "disabled"}),
+		} {
+			if err := k8sClient.Create(ctx, namespace); err != nil {
+				Expect(apierrors.IsAlreadyExists(err)).To(BeTrue())
+			}
+		}
+	})
+
+	AfterEach(func() {
+		for _, namespace := range []string{"scope-in", "scope-selected", "scope-out"} {
+			Expect(k8sClient.DeleteAllOf(ctx, &corev1.Pod{}, client.InNamespace(namespace), client.GracePeriodSeconds(0))).To(Succeed())
+		}
+	})
+
+	It("only deletes pods from the monkey's namespace", func() {
+		CreateRunningPod(ctx, EnvtestPod("in-scope", "scope-in", selector))
+		for _, name := range []string{"out-of-scope-1", "out-of-scope-2", "out-of-scope-3"} {
+			CreateRunningPod(ctx, EnvtestPod(name, "scope-out", selector))
+		}
+
+		// experiments are claimed on the stored monkey before any pod is deleted
+		monkey := Monkey("scoped", "5m", "scope-in", false, selector, []metav1.Condition{})
+		Expect(k8sClient.Create(ctx, monkey)).To(Succeed())
+		defer func() {
+			Expect(k8sClient.Delete(ctx, monkey)).To(Succeed())
+		}()
+
+		r := &MonkeyReconciler{Client: k8sClient, Scheme: scheme.Scheme}
+		for i := 0; i < 3; i++ {
+			_, err := r.PerformExperiment(ctx, monkey)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		inScope := &corev1.PodList{}
+		Expect(k8sClient.List(ctx, inScope, client.InNamespace("scope-in"))).To(Succeed())
+		Expect(inScope.Items).To(BeEmpty())
+		outOfScope := &corev1.PodList{}
+		Expect(k8sClient.List(ctx, outOfScope, client.InNamespace("scope-out"))).To(Succeed())
+		Expect(outOfScope.Items).To(HaveLen(3))
+	})
+
+	It("only chooses pods from namespaces matching the namespace selector", func() {
+		CreateRunningPod(ctx, EnvtestPod("selected", "scope-selected", selector))
+		CreateRunningPod(ctx, EnvtestPod("listed", "scope-in", selector))
+		CreateRunningPod(ctx, EnvtestPod("not-selected", "scope-out", selector))
+
+		monkey := Monkey("selected", "5m", "default", false, selector, []metav1.Condition{})
+		monkey.Spec.Namespace = ""
+		monkey.Spec.Namespaces = []string{"scope-in"}
+		monkey.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"chaos": "enabled"}}
+
+		r := &MonkeyReconciler{Client: k8sClient, Scheme: scheme.Scheme}
+		namespaces, err := r.GetTargetNamespaces(ctx, monkey)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(namespaces).To(ConsistOf("scope-in", "scope-selected"))
+		for i := 0; i < 20; i++ {
+			target, err := r.GetTarget(ctx, namespaces, monkey.Spec.Selector)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(target.GetNamespace()).NotTo(Equal("scope-out"))
+		}
+	})
+})
+
+// CreateRunningPod creates the pod provided and marks it as running and ready, as envtest has no kubelet to do so
+func CreateRunningPod(ctx context.Context, pod *corev1.Pod) {
+	Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+	pod.Status = corev1.PodStatus{
+		Phase:      corev1.PodRunning,
+		Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+	}
+	Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+}
+
+func EnvtestPod(name, namespace string, labels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "app", Image: "nginx"},
+			},
+		},
+	}
+}
+
+func TestGetKillCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     podchaosv1alpha1.KillMode
+		value    int32
+		matching int
+		want     int
+		wantErr  bool
+	}{
+		{name: "default", mode: "", matching: 5, want: 1},
+		{name: "one", mode: podchaosv1alpha1.KillModeOne, matching: 5, want: 1},
+		{name: "one of none", mode: podchaosv1alpha1.KillModeOne, matching: 0, want: 0},
+		{name: "fixed", mode: podchaosv1alpha1.KillModeFixed, value: 3, matching: 5, want: 3},
+		{name: "fixed more than matching", mode: podchaosv1alpha1.KillModeFixed, value: 3, matching: 2, want: 2},
+		{name: "fixed without value", mode: podchaosv1alpha1.KillModeFixed, matching: 5, wantErr: true},
+		{name: "percent", mode: podchaosv1alpha1.KillModePercent, value: 30, matching: 10, want: 3},
+		{name: "percent rounds down", mode: podchaosv1alpha1.KillModePercent, value: 30, matching: 9, want: 2},
+		{name: "percent at least one", mode: podchaosv1alpha1.KillModePercent, value: 10, matching: 3, want: 1},
+		{name: "percent of none", mode: podchaosv1alpha1.KillModePercent, value: 10, matching: 0, want: 0},
+		{name: "percent over 100", mode: podchaosv1alpha1.KillModePercent, value: 101, matching: 5, wantErr: true},
+		{name: "all", mode: podchaosv1alpha1.KillModeAll, matching: 5, want: 5},
+		{name: "unknown", mode: "most", matching: 5, wantErr: true},
+	}
+	g := NewWithT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetKillCount(tt.mode, tt.value, tt.matching)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(got).Should(Equal(tt.want))
+			}
+		})
+	}
+}
+
+func TestMonkeyReconciler_PerformExperimentModes(t *testing.T) {
+	tests := []struct {
+		name         string
+		mode         podchaosv1alpha1.KillMode
+		value        int32
+		noop         bool
+		wantPodCount int
+		wantVictims  int
+		wantHistory  int
+	}{
+		{name: "one", mode: podchaosv1alpha1.KillModeOne, wantPodCount: 9, wantVictims: 1, wantHistory: 1},
+		{name: "fixed", mode: podchaosv1alpha1.KillModeFixed, value: 3, wantPodCount: 7, wantVictims: 3, wantHistory: 3},
+		{name: "percent", mode: podchaosv1alpha1.KillModePercent, value: 50, wantPodCount: 5, wantVictims: 5, wantHistory: 5},
+		{name: "all", mode: podchaosv1alpha1.KillModeAll, wantPodCount: 0, wantVictims: 10, wantHistory: 10},
+		{name: "noop", mode: podchaosv1alpha1.KillModeAll, noop: true, wantPodCount: 10, wantVictims: 0, wantHistory: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			g := NewWithT(t)
+			monkey := Monkey("modes", "5m", "workloads", tt.noop, map[string]string{"allowChaos": "true"}, []metav1.Condition{})
+			monkey.Spec.Mode = tt.mode
+			monkey.Spec.Value = tt.value
+			objs := []client.Object{monkey.DeepCopy()}
+			for i := 0; i < 10; i++ {
+				pod := Pod(fmt.Sprintf("pod-%d", i), fmt.Sprintf("%d", i), "workloads", "true")
+				objs = append(objs, &pod)
+			}
+			c, fakeScheme := InitTests(t, objs...)
+			r := &MonkeyReconciler{
+				Client: c,
+				Scheme: fakeScheme,
+			}
+
+			_, err := r.PerformExperiment(ctx, monkey)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			pods := &corev1.PodList{}
+			g.Expect(r.List(ctx, pods)).To(Succeed())
+			g.Expect(pods.Items).Should(HaveLen(tt.wantPodCount))
+			stored := &podchaosv1alpha1.Monkey{}
+			g.Expect(r.Get(ctx, client.ObjectKeyFromObject(monkey), stored)).To(Succeed())
+			g.Expect(stored.Status.LastVictims).Should(HaveLen(tt.wantVictims))
+			g.Expect(stored.Status.TotalKills).Should(Equal(int64(tt.wantVictims)))
+			g.Expect(stored.Status.History).Should(HaveLen(tt.wantHistory))
+			for _, record := range stored.Status.History {
+				g.Expect(record.Noop).Should(Equal(tt.noop))
+				if tt.noop {
+					g.Expect(record.Result).Should(Equal(podchaosv1alpha1.ResultSkipped))
+				} else {
+					g.Expect(record.Result).Should(Equal(podchaosv1alpha1.ResultSucceeded))
+				}
+			}
+			seen := map[string]bool{}
+			for _, victim := range stored.Status.LastVictims {
+				g.Expect(seen[victim.Name]).Should(BeFalse(), "pod %s chosen twice", victim.Name)
+				seen[victim.Name] = true
+				g.Expect(victim.Namespace).Should(Equal("workloads"))
+				g.Expect(victim.UID).ShouldNot(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestIsEligible(t *testing.T) {
+	running := Pod("running", "1", "workloads", "true")
+	unready := *running.DeepCopy()
+	unready.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}}
+	pending := Pod("pending", "2", "workloads", "true")
+	pending.Status = corev1.PodStatus{Phase: corev1.PodPending}
+	succeeded := Pod("succeeded", "3", "workloads", "true")
+	succeeded.Status.Phase = corev1.PodSucceeded
+	terminating := *running.DeepCopy()
+	terminating.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	tests := []struct {
+		name   string
+		pod    corev1.Pod
+		policy *podchaosv1alpha1.CandidatePolicy
+		want   bool
+	}{
+		{name: "running and ready", pod: running, want: true},
+		{name: "unready", pod: unready, want: false},
+		{name: "unready included", pod: unready, policy: &podchaosv1alpha1.CandidatePolicy{IncludeUnready: true}, want: true},
+		{name: "pending", pod: pending, want: false},
+		{name: "pending included", pod: pending, policy: &podchaosv1alpha1.CandidatePolicy{Phases: []corev1.PodPhase{corev1.PodPending}, IncludeUnready: true}, want: true},
+		{name: "succeeded", pod: succeeded, want: false},
+		{name: "running excluded by phases", pod: running, policy: &podchaosv1alpha1.CandidatePolicy{Phases: []corev1.PodPhase{corev1.PodPending}}, want: false},
+		{name: "terminating", pod: terminating, want: false},
+		{name: "terminating included", pod: terminating, policy: &podchaosv1alpha1.CandidatePolicy{IncludeTerminating: true}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(IsEligible(&tt.pod, tt.policy)).Should(Equal(tt.want))
+		})
+	}
+}
+
+func TestMonkeyReconciler_PerformExperimentCandidates(t *testing.T) {
+	ctx := context.Background()
+	g := NewWithT(t)
+	monkey := Monkey("candidates", "5m", "workloads", false, map[string]string{"allowChaos": "true"}, []metav1.Condition{})
+	monkey.Spec.Mode = podchaosv1alpha1.KillModeAll
+	running := Pod("running", "1", "workloads", "true")
+	pending := Pod("pending", "2", "workloads", "true")
+	pending.Status = corev1.PodStatus{Phase: corev1.PodPending}
+	failed := Pod("failed", "3", "workloads", "true")
+	failed.Status = corev1.PodStatus{Phase: corev1.PodFailed}
+	terminating := Pod("terminating", "4", "workloads", "true")
+	terminating.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	c, fakeScheme := InitTests(t, monkey.DeepCopy(), &running, &pending, &failed, &terminating)
+	r := &MonkeyReconciler{
+		Client: c,
+		Scheme: fakeScheme,
+	}
+
+	_, err := r.PerformExperiment(ctx, monkey)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	stored := &podchaosv1alpha1.Monkey{}
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(monkey), stored)).To(Succeed())
+	g.Expect(stored.Status.LastVictims).Should(HaveLen(1))
+	g.Expect(stored.Status.LastVictims[0].Name).Should(Equal("running"))
+	pods := &corev1.PodList{}
+	g.Expect(r.List(ctx, pods)).To(Succeed())
+	g.Expect(pods.Items).Should(HaveLen(3))
+}
+
+func TestIsExcluded(t *testing.T) {
+	exclude, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchLabels: map[string]string{"role": "leader"}})
+	NewWithT(t).Expect(err).ToNot(HaveOccurred())
+	plain := Pod("plain", "1", "workloads", "true")
+	annotated := Pod("annotated", "2", "workloads", "true")
+	annotated.Annotations = map[string]string{podchaosv1alpha1.ExcludeAnnotation: "true"}
+	optedIn := Pod("opted-in", "3", "workloads", "true")
+	optedIn.Annotations = map[string]string{podchaosv1alpha1.ExcludeAnnotation: "false"}
+	leader := Pod("leader", "4", "workloads", "true")
+	leader.Labels["role"] = "leader"
+	tests := []struct {
+		name    string
+		pod     corev1.Pod
+		exclude labels.Selector
+		want    bool
+	}{
+		{name: "plain", pod: plain, exclude: exclude, want: false},
+		{name: "annotated", pod: annotated, want: true},
+		{name: "annotated false", pod: optedIn, want: false},
+		{name: "matches exclude selector", pod: leader, exclude: exclude, want: true},
+		{name: "no exclude selector", pod: leader, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(IsExcluded(&tt.pod, tt.exclude)).Should(Equal(tt.want))
+		})
+	}
+}
+
+func TestMonkeyReconciler_PerformExperimentExclusions(t *testing.T) {
+	ctx := context.Background()
+	g := NewWithT(t)
+	monkey := Monkey("exclusions", "5m", "workloads", false, map[string]string{"allowChaos": "true"}, []metav1.Condition{})
+	monkey.Spec.Mode = podchaosv1alpha1.KillModeAll
+	monkey.Spec.ExcludeSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"role": "leader"}}
+	plain := Pod("plain", "1", "workloads", "true")
+	migration := Pod("migration", "2", "workloads", "true")
+	migration.Annotations = map[string]string{podchaosv1alpha1.ExcludeAnnotation: "true"}
+	leader := Pod("leader", "3", "workloads", "true")
+	leader.Labels["role"] = "leader"
+	c, fakeScheme := InitTests(t, monkey.DeepCopy(), &plain, &migration, &leader)
+	r := &MonkeyReconciler{
+		Client: c,
+		Scheme: fakeScheme,
+	}
+
+	_, err := r.PerformExperiment(ctx, monkey)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	stored := &podchaosv1alpha1.Monkey{}
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(monkey), stored)).To(Succeed())
+	g.Expect(stored.Status.LastVictims).Should(HaveLen(1))
+	g.Expect(stored.Status.LastVictims[0].Name).Should(Equal("plain"))
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(&migration), &migration)).To(Succeed())
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(&leader), &leader)).To(Succeed())
+}
+
+func TestMonkeyReconciler_PerformExperimentEviction(t *testing.T) {
+	ctx := context.Background()
+	g := NewWithT(t)
+	monkey := Monkey("evict", "5m", "workloads", false, map[string]string{"allowChaos": "true"}, []metav1.Condition{})
+	monkey.Spec.UseEviction = true
+	monkey.Spec.Mode = podchaosv1alpha1.KillModeAll
+	evictable := Pod("evictable", "1", "workloads", "true")
+	guarded := Pod("guarded", "2", "workloads", "true")
+	c, fakeScheme := InitTests(t, monkey.DeepCopy(), &evictable, &guarded)
+
+	clientset := k8sfake.NewSimpleClientset()
+	var evicted []string
+	clientset.PrependReactor("create", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		eviction := action.(clienttesting.CreateAction).GetObject().(*policyv1.Eviction)
+		if eviction.GetName() == "guarded" {
+			return true, nil, apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+		}
+		evicted = append(evicted, eviction.GetName())
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: eviction.GetName(), Namespace: eviction.GetNamespace()}}
+		return true, nil, c.Delete(ctx, pod)
+	})
+	r := &MonkeyReconciler{
+		Client:    c,
+		Scheme:    fakeScheme,
+		Clientset: clientset,
+	}
+
+	got, err := r.PerformExperiment(ctx, monkey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).Should(Equal(ctrl.Result{RequeueAfter: 5 * time.Minute}))
+	g.Expect(evicted).Should(Equal([]string{"evictable"}))
+
+	pods := &corev1.PodList{}
+	g.Expect(r.List(ctx, pods)).To(Succeed())
+	g.Expect(pods.Items).Should(HaveLen(1))
+	g.Expect(pods.Items[0].GetName()).Should(Equal("guarded"))
+
+	stored := &podchaosv1alpha1.Monkey{}
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(monkey), stored)).To(Succeed())
+	g.Expect(stored.Status.LastVictims).Should(HaveLen(1))
+	blocked := meta.FindStatusCondition(stored.Status.Conditions, podchaosv1alpha1.ConditionEvictionBlocked)
+	g.Expect(blocked).ShouldNot(BeNil())
+	g.Expect(blocked.Status).Should(Equal(metav1.ConditionTrue))
+	g.Expect(blocked.Reason).Should(Equal(podchaosv1alpha1.ReasonPodDisruptionBudget))
+	g.Expect(blocked.Message).Should(ContainSubstring("workloads/guarded"))
+}
+
+func TestGetGracePeriodSeconds(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(GetGracePeriodSeconds(podchaosv1alpha1.MonkeySpec{})).Should(Equal(int64ToPointerint64(0)))
+	g.Expect(GetGracePeriodSeconds(podchaosv1alpha1.MonkeySpec{GracePeriodSeconds: int64ToPointerint64(10)})).Should(Equal(int64ToPointerint64(10)))
+	g.Expect(GetGracePeriodSeconds(podchaosv1alpha1.MonkeySpec{GracePeriodSeconds: int64ToPointerint64(podchaosv1alpha1.UsePodGracePeriod)})).Should(BeNil())
+}
+
+func TestMonkeyReconciler_PerformExperimentGracePeriod(t *testing.T) {
+	tests := []struct {
+		name               string
+		gracePeriodSeconds *int64
+		want               *int64
+	}{
+		{name: "immediate by default", gracePeriodSeconds: nil, want: int64ToPointerint64(0)},
+		{name: "graceful", gracePeriodSeconds: int64ToPointerint64(10), want: int64ToPointerint64(10)},
+		{name: "pod default", gracePeriodSeconds: int64ToPointerint64(podchaosv1alpha1.UsePodGracePeriod), want: int64ToPointerint64(45)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			g := NewWithT(t)
+			monkey := Monkey("graceful", "5m", "workloads", false, map[string]string{"allowChaos": "true"}, []metav1.Condition{})
+			monkey.Spec.GracePeriodSeconds = tt.gracePeriodSeconds
+			pod := Pod("delete", "1", "workloads", "true")
+			pod.Spec.TerminationGracePeriodSeconds = int64ToPointerint64(45)
+			c, fakeScheme := InitTests(t, monkey.DeepCopy(), &pod)
+			r := &MonkeyReconciler{
+				Client: c,
+				Scheme: fakeScheme,
+			}
+
+			_, err := r.PerformExperiment(ctx, monkey)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			stored := &podchaosv1alpha1.Monkey{}
+			g.Expect(r.Get(ctx, client.ObjectKeyFromObject(monkey), stored)).To(Succeed())
+			g.Expect(stored.Status.LastVictims).Should(HaveLen(1))
+			g.Expect(stored.Status.LastVictims[0].GracePeriodSeconds).Should(Equal(tt.want))
+			g.Expect(stored.Status.History).Should(HaveLen(1))
+			g.Expect(stored.Status.History[0].GracePeriodSeconds).Should(Equal(tt.want))
+		})
+	}
+}
+
+func TestMonkeyReconciler_PerformExperimentEvents(t *testing.T) {
+	isController := true
+	owned := Pod("nginx-66b6c48dd5-abcde", "1", "workloads", "true")
+	owned.OwnerReferences = []metav1.OwnerReference{
+		{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "nginx-66b6c48dd5", UID: "nginx-66b6c48dd5", Controller: &isController},
+	}
+	owned.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	replicaSet := ReplicaSet("nginx-66b6c48dd5", "workloads", 1)
+	tests := []struct {
+		name       string
+		noop       bool
+		pods       []client.Object
+		wantEvents []string
+	}{
+		{
+			name: "pod killed",
+			pods: []client.Object{owned.DeepCopy(), replicaSet.DeepCopy()},
+			wantEvents: []string{
+				"Normal PodKilled Deleted pod workloads/nginx-66b6c48dd5-abcde",
+				"Normal PodKilled Pod workloads/nginx-66b6c48dd5-abcde was deleted by Monkey workloads/events",
+			},
+		},
+		{
+			name: "noop kill",
+			noop: true,
+			pods: []client.Object{owned.DeepCopy(), replicaSet.DeepCopy()},
+			wantEvents: []string{
+				"Normal NoopKill Would have deleted pod workloads/nginx-66b6c48dd5-abcde",
+			},
+		},
+		{
+			name: "target unhealthy",
+			pods: []client.Object{owned.DeepCopy(), ReplicaSet("nginx-66b6c48dd5", "workloads", 2)},
+			wantEvents: []string{
+				"Warning TargetUnhealthy Skipping experiment as targets are not in a steady state: ReplicaSet workloads/nginx-66b6c48dd5 has 1 of 2 replicas ready",
+			},
+		},
+		{
+			name: "no target",
+			wantEvents: []string{
+				"Normal NoTarget No eligible pods matching the selector found in namespaces: workloads",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			g := NewWithT(t)
+			monkey := Monkey("events", "5m", "workloads", tt.noop, map[string]string{"allowChaos": "true"}, []metav1.Condition{})
+			c, fakeScheme := InitTests(t, append(tt.pods, monkey.DeepCopy())...)
+			recorder := record.NewFakeRecorder(10)
+			r := &MonkeyReconciler{
+				Client:   c,
+				Scheme:   fakeScheme,
+				Recorder: recorder,
+			}
+
+			_, err := r.PerformExperiment(ctx, monkey)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			close(recorder.Events)
+			var events []string
+			for event := range recorder.Events {
+				events = append(events, event)
 			}
+			g.Expect(events).Should(Equal(tt.wantEvents))
 		})
 	}
 }
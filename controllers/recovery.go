@@ -0,0 +1,228 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	podchaosv1alpha1 "github.com/perithompson/podchaosmonkey/api/v1alpha1"
+)
+
+// RecoveryPollInterval is how often pending recovery checks are evaluated
+const RecoveryPollInterval = 5 * time.Second
+
+//+kubebuilder:rbac:groups=apps,resources=replicasets;statefulsets;daemonsets,verbs=get;list;watch
+
+//GetRecoveryTimeout returns the recovery timeout of the spec provided, or 0 when recovery checks are disabled
+func GetRecoveryTimeout(spec podchaosv1alpha1.MonkeySpec) (time.Duration, error) {
+	if spec.RecoveryTimeout == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(spec.RecoveryTimeout)
+	if err != nil {
+		return 0, err
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("recoveryTimeout must be positive, got %s", spec.RecoveryTimeout)
+	}
+	return timeout, nil
+}
+
+//GetWorkload returns the ReplicaSet, StatefulSet or DaemonSet controlling the pod provided, or nil when the pod is not
+//controlled by one of them
+func GetWorkload(pod *corev1.Pod) *podchaosv1alpha1.WorkloadReference {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return nil
+	}
+	switch owner.Kind {
+	case "ReplicaSet", "StatefulSet", "DaemonSet":
+	default:
+		return nil
+	}
+	return &podchaosv1alpha1.WorkloadReference{
+		APIVersion: owner.APIVersion,
+		Kind:       owner.Kind,
+		Name:       owner.Name,
+		UID:        owner.UID,
+	}
+}
+
+//NewRecoveryCheck returns a recovery check for the workload owning the pod provided, or nil when the pod is not owned
+//by a ReplicaSet, StatefulSet or DaemonSet
+func NewRecoveryCheck(pod *corev1.Pod, now time.Time, timeout time.Duration) *podchaosv1alpha1.RecoveryCheck {
+	owner := GetWorkload(pod)
+	if owner == nil {
+		return nil
+	}
+	return &podchaosv1alpha1.RecoveryCheck{
+		Namespace: pod.GetNamespace(),
+		Owner:     *owner,
+		PodName:   pod.GetName(),
+		PodUID:    pod.GetUID(),
+		StartTime: metav1.NewTime(now),
+		Deadline:  metav1.NewTime(now.Add(timeout)),
+	}
+}
+
+//IsPodReady returns true when the pod provided has a Ready condition that is true
+func IsPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+//GetDesiredReplicas returns the number of ready replicas the workload provided should have
+func (r *MonkeyReconciler) GetDesiredReplicas(ctx context.Context, namespace string, owner podchaosv1alpha1.WorkloadReference) (int32, error) {
+	key := types.NamespacedName{Namespace: namespace, Name: owner.Name}
+	switch owner.Kind {
+	case "ReplicaSet":
+		replicaSet := &appsv1.ReplicaSet{}
+		if err := r.Get(ctx, key, replicaSet); err != nil {
+			return 0, err
+		}
+		if replicaSet.Spec.Replicas == nil {
+			return 1, nil
+		}
+		return *replicaSet.Spec.Replicas, nil
+	case "StatefulSet":
+		statefulSet := &appsv1.StatefulSet{}
+		if err := r.Get(ctx, key, statefulSet); err != nil {
+			return 0, err
+		}
+		if statefulSet.Spec.Replicas == nil {
+			return 1, nil
+		}
+		return *statefulSet.Spec.Replicas, nil
+	case "DaemonSet":
+		daemonSet := &appsv1.DaemonSet{}
+		if err := r.Get(ctx, key, daemonSet); err != nil {
+			return 0, err
+		}
+		return daemonSet.Status.DesiredNumberScheduled, nil
+	}
+	return 0, fmt.Errorf("unsupported workload kind %s", owner.Kind)
+}
+
+//GetWorkloadPods returns the pods controlled by the workload provided that are not terminating
+func (r *MonkeyReconciler) GetWorkloadPods(ctx context.Context, namespace string, owner podchaosv1alpha1.WorkloadReference) ([]corev1.Pod, error) {
+	var list corev1.PodList
+	if err := r.List(ctx, &list, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	var pods []corev1.Pod
+	for _, pod := range list.Items {
+		controller := metav1.GetControllerOf(&pod)
+		if controller == nil || controller.UID != owner.UID || pod.GetDeletionTimestamp() != nil {
+			continue
+		}
+		pods = append(pods, pod)
+	}
+	return pods, nil
+}
+
+//GetReadyReplicas counts the ready pods of the workload provided, ignoring terminating pods and the pod being replaced
+func (r *MonkeyReconciler) GetReadyReplicas(ctx context.Context, namespace string, owner podchaosv1alpha1.WorkloadReference, ignoreUID types.UID) (int32, error) {
+	pods, err := r.GetWorkloadPods(ctx, namespace, owner)
+	if err != nil {
+		return 0, err
+	}
+	var ready int32
+	for i := range pods {
+		if pods[i].GetUID() != ignoreUID && IsPodReady(&pods[i]) {
+			ready++
+		}
+	}
+	return ready, nil
+}
+
+//IsWorkloadRecovered returns true when the workload of the recovery check has its desired number of ready replicas. A
+//NotFound error is returned when the workload no longer exists
+func (r *MonkeyReconciler) IsWorkloadRecovered(ctx context.Context, check *podchaosv1alpha1.RecoveryCheck) (bool, error) {
+	desired, err := r.GetDesiredReplicas(ctx, check.Namespace, check.Owner)
+	if err != nil {
+		return false, err
+	}
+	ready, err := r.GetReadyReplicas(ctx, check.Namespace, check.Owner, check.PodUID)
+	if err != nil {
+		return false, err
+	}
+	return ready >= desired, nil
+}
+
+//CheckRecoveries evaluates the pending recovery checks of the monkey, returning true when the status changed. The checks
+//of workloads that were deleted since are dropped without a result, as there is nothing left to recover
+func (r *MonkeyReconciler) CheckRecoveries(ctx context.Context, monkey *podchaosv1alpha1.Monkey, now time.Time) (bool, error) {
+	monkeySay := ctrl.Log.WithName("controller").WithName("monkey")
+	var pending []podchaosv1alpha1.RecoveryCheck
+	changed, dropped := false, false
+	for i := range monkey.Status.PendingRecoveries {
+		check := monkey.Status.PendingRecoveries[i]
+		workload := fmt.Sprintf("%s %s/%s", check.Owner.Kind, check.Namespace, check.Owner.Name)
+		recovered, err := r.IsWorkloadRecovered(ctx, &check)
+		if apierrors.IsNotFound(err) {
+			monkeySay.Info(fmt.Sprintf("%s was deleted, dropping its recovery check", workload))
+			dropped = true
+			continue
+		}
+		if err != nil {
+			return changed || dropped, err
+		}
+		switch {
+		case recovered:
+			check.Result = podchaosv1alpha1.RecoveryPassed
+			check.TimeToRecovery = &metav1.Duration{Duration: now.Sub(check.StartTime.Time).Round(time.Second)}
+			monkeySay.Info(fmt.Sprintf("%s recovered after %s", workload, check.TimeToRecovery.Duration))
+			r.recordEvent(monkey, corev1.EventTypeNormal, EventReasonRecovered, "%s recovered after %s", workload, check.TimeToRecovery.Duration)
+			TimeToRecoverySeconds.WithLabelValues(monkey.GetName(), monkey.GetNamespace()).Observe(check.TimeToRecovery.Seconds())
+		case !now.Before(check.Deadline.Time):
+			check.Result = podchaosv1alpha1.RecoveryFailed
+			monkeySay.Info(fmt.Sprintf("%s did not recover before %s", workload, check.Deadline.UTC().Format(time.RFC3339)))
+			r.recordEvent(monkey, corev1.EventTypeWarning, EventReasonRecoveryFailed, "%s did not recover within %s", workload, check.Deadline.Sub(check.StartTime.Time))
+		default:
+			pending = append(pending, check)
+			continue
+		}
+		RecoveryChecksTotal.WithLabelValues(monkey.GetName(), monkey.GetNamespace(), string(check.Result)).Inc()
+		monkey.Status.LastRecovery = check.DeepCopy()
+		if check.Result == podchaosv1alpha1.RecoveryFailed {
+			monkey.Status.ConsecutiveRecoveryFailures++
+		} else {
+			monkey.Status.ConsecutiveRecoveryFailures = 0
+		}
+		changed = true
+	}
+	monkey.Status.PendingRecoveries = pending
+	if policy := monkey.Spec.AbortPolicy; changed && policy != nil && policy.MaxConsecutiveRecoveryFailures > 0 &&
+		monkey.Status.ConsecutiveRecoveryFailures >= policy.MaxConsecutiveRecoveryFailures {
+		r.halt(monkey, podchaosv1alpha1.ReasonRecoveryFailures, fmt.Sprintf("%d recovery checks failed in a row", monkey.Status.ConsecutiveRecoveryFailures))
+	}
+	return changed || dropped, nil
+}
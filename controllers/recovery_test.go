@@ -0,0 +1,243 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	podchaosv1alpha1 "github.com/perithompson/podchaosmonkey/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestGetRecoveryTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "disabled", timeout: "", want: 0},
+		{name: "enabled", timeout: "2m", want: 2 * time.Minute},
+		{name: "invalid", timeout: "soon", wantErr: true},
+		{name: "negative", timeout: "-1m", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			got, err := GetRecoveryTimeout(podchaosv1alpha1.MonkeySpec{RecoveryTimeout: tt.timeout})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).Should(Equal(tt.want))
+		})
+	}
+}
+
+func TestNewRecoveryCheck(t *testing.T) {
+	now := time.Date(2022, time.April, 8, 17, 30, 0, 0, time.UTC)
+	g := NewWithT(t)
+
+	pod := ReplicaPod("nginx-66b6c48dd5-abcde", "1", "workloads", "ReplicaSet", "nginx-66b6c48dd5", true)
+	g.Expect(NewRecoveryCheck(pod, now, time.Minute)).Should(Equal(&podchaosv1alpha1.RecoveryCheck{
+		Namespace: "workloads",
+		Owner: podchaosv1alpha1.WorkloadReference{
+			APIVersion: "apps/v1",
+			Kind:       "ReplicaSet",
+			Name:       "nginx-66b6c48dd5",
+			UID:        "nginx-66b6c48dd5",
+		},
+		PodName:   "nginx-66b6c48dd5-abcde",
+		PodUID:    "1",
+		StartTime: metav1.NewTime(now),
+		Deadline:  metav1.NewTime(now.Add(time.Minute)),
+	}))
+
+	job := ReplicaPod("backup-abcde", "2", "workloads", "Job", "backup", true)
+	g.Expect(NewRecoveryCheck(job, now, time.Minute)).Should(BeNil())
+
+	orphan := Pod("orphan", "3", "workloads", "true")
+	g.Expect(NewRecoveryCheck(&orphan, now, time.Minute)).Should(BeNil())
+}
+
+func TestMonkeyReconciler_CheckRecoveries(t *testing.T) {
+	start := time.Date(2022, time.April, 8, 17, 30, 0, 0, time.UTC)
+	replicaSet := ReplicaSet("nginx", "workloads", 2)
+	terminating := ReplicaPod("nginx-terminating", "3", "workloads", "ReplicaSet", "nginx", true)
+	terminating.DeletionTimestamp = &metav1.Time{Time: start}
+	terminating.Finalizers = []string{"test"}
+	tests := []struct {
+		name        string
+		objs        []client.Object
+		now         time.Time
+		wantChanged bool
+		wantPending int
+		wantResult  podchaosv1alpha1.RecoveryResult
+	}{
+		{
+			name: "recovered",
+			objs: []client.Object{
+				replicaSet,
+				ReplicaPod("nginx-a", "2", "workloads", "ReplicaSet", "nginx", true),
+				ReplicaPod("nginx-b", "4", "workloads", "ReplicaSet", "nginx", true),
+			},
+			now:         start.Add(20 * time.Second),
+			wantChanged: true,
+			wantResult:  podchaosv1alpha1.RecoveryPassed,
+		},
+		{
+			name: "replacement not ready",
+			objs: []client.Object{
+				replicaSet,
+				ReplicaPod("nginx-a", "2", "workloads", "ReplicaSet", "nginx", true),
+				ReplicaPod("nginx-b", "4", "workloads", "ReplicaSet", "nginx", false),
+			},
+			now:         start.Add(20 * time.Second),
+			wantPending: 1,
+		},
+		{
+			name: "victim and terminating pods are ignored",
+			objs: []client.Object{
+				replicaSet,
+				ReplicaPod("nginx-a", "2", "workloads", "ReplicaSet", "nginx", true),
+				ReplicaPod("nginx-victim", "1", "workloads", "ReplicaSet", "nginx", true),
+				terminating,
+			},
+			now:         start.Add(20 * time.Second),
+			wantPending: 1,
+		},
+		{
+			name: "timed out",
+			objs: []client.Object{
+				replicaSet,
+				ReplicaPod("nginx-a", "2", "workloads", "ReplicaSet", "nginx", true),
+			},
+			now:         start.Add(time.Minute),
+			wantChanged: true,
+			wantResult:  podchaosv1alpha1.RecoveryFailed,
+		},
+		{
+			name:        "workload deleted",
+			objs:        []client.Object{},
+			now:         start.Add(20 * time.Second),
+			wantChanged: true,
+		},
+		{
+			name:        "workload deleted after the deadline",
+			objs:        []client.Object{},
+			now:         start.Add(time.Minute),
+			wantChanged: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			g := NewWithT(t)
+			c, fakeScheme := InitTests(t, tt.objs...)
+			r := &MonkeyReconciler{
+				Client: c,
+				Scheme: fakeScheme,
+			}
+			monkey := Monkey("recovery", "5m", "workloads", false, map[string]string{}, []metav1.Condition{})
+			monkey.Status.PendingRecoveries = []podchaosv1alpha1.RecoveryCheck{{
+				Namespace: "workloads",
+				Owner:     podchaosv1alpha1.WorkloadReference{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "nginx", UID: "nginx"},
+				PodName:   "nginx-victim",
+				PodUID:    "1",
+				StartTime: metav1.NewTime(start),
+				Deadline:  metav1.NewTime(start.Add(time.Minute)),
+			}}
+
+			changed, err := r.CheckRecoveries(ctx, monkey, tt.now)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(changed).Should(Equal(tt.wantChanged))
+			g.Expect(monkey.Status.PendingRecoveries).Should(HaveLen(tt.wantPending))
+			if tt.wantResult == "" {
+				g.Expect(monkey.Status.LastRecovery).Should(BeNil())
+				g.Expect(monkey.Status.ConsecutiveRecoveryFailures).Should(BeZero())
+				return
+			}
+			g.Expect(monkey.Status.LastRecovery).ShouldNot(BeNil())
+			g.Expect(monkey.Status.LastRecovery.Result).Should(Equal(tt.wantResult))
+			if tt.wantResult == podchaosv1alpha1.RecoveryPassed {
+				g.Expect(monkey.Status.LastRecovery.TimeToRecovery).Should(Equal(&metav1.Duration{Duration: 20 * time.Second}))
+			}
+		})
+	}
+}
+
+func TestMonkeyReconciler_PerformExperimentRecovery(t *testing.T) {
+	ctx := context.Background()
+	g := NewWithT(t)
+	monkey := Monkey("recovery", "5m", "workloads", false, map[string]string{"allowChaos": "true"}, []metav1.Condition{})
+	monkey.Spec.RecoveryTimeout = "2m"
+	pod := ReplicaPod("nginx-a", "1", "workloads", "ReplicaSet", "nginx", true)
+	pod.Labels = map[string]string{"allowChaos": "true"}
+	c, fakeScheme := InitTests(t, monkey.DeepCopy(), pod, ReplicaSet("nginx", "workloads", 1))
+	r := &MonkeyReconciler{
+		Client: c,
+		Scheme: fakeScheme,
+	}
+
+	_, err := r.PerformExperiment(ctx, monkey)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	stored := &podchaosv1alpha1.Monkey{}
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(monkey), stored)).To(Succeed())
+	g.Expect(stored.Status.PendingRecoveries).Should(HaveLen(1))
+	check := stored.Status.PendingRecoveries[0]
+	g.Expect(check.Owner.Name).Should(Equal("nginx"))
+	g.Expect(check.PodUID).Should(Equal(types.UID("1")))
+	g.Expect(check.Deadline.Sub(check.StartTime.Time)).Should(Equal(2 * time.Minute))
+}
+
+func ReplicaPod(name, uid, namespace, ownerKind, ownerName string, ready bool) *corev1.Pod {
+	isController := true
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			UID:       types.UID(uid),
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: ownerKind, Name: ownerName, UID: types.UID(ownerName), Controller: &isController},
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func ReplicaSet(name, namespace string, replicas int32) *appsv1.ReplicaSet {
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, UID: types.UID(name)},
+		Spec:       appsv1.ReplicaSetSpec{Replicas: &replicas},
+	}
+}
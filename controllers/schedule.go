@@ -0,0 +1,178 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	podchaosv1alpha1 "github.com/perithompson/podchaosmonkey/api/v1alpha1"
+)
+
+//GetNextExperimentTime returns when the next Chaos experiment should run after the time provided
+func GetNextExperimentTime(spec podchaosv1alpha1.MonkeySpec, after time.Time) (time.Time, error) {
+	if spec.Schedule == "" {
+		interval, err := GetInterval(spec.Interval, spec.MaxInterval)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return after.Add(interval), nil
+	}
+	schedule, err := podchaosv1alpha1.ParseSchedule(spec.Schedule, spec.TimeZone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	next := schedule.Next(after)
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("schedule %q never runs", spec.Schedule)
+	}
+	return next, nil
+}
+
+//ScheduleStartingDeadline is how late the experiment of a scheduled monkey may still run. An experiment missed by more,
+//e.g. while the controller was down, is skipped rather than run at a time its schedule does not allow
+const ScheduleStartingDeadline = time.Minute
+
+//SkipMissedExperiment plans the next experiment of a scheduled monkey from the time provided when the planned experiment
+//was missed by more than ScheduleStartingDeadline. It returns true when the experiment was skipped
+func SkipMissedExperiment(monkey *podchaosv1alpha1.Monkey, now time.Time) (bool, error) {
+	next := monkey.Status.NextExperimentTime
+	if monkey.Spec.Schedule == "" || next == nil || now.Sub(next.Time) <= ScheduleStartingDeadline {
+		return false, nil
+	}
+	planned, err := GetNextExperimentTime(monkey.Spec, now)
+	if err != nil {
+		return false, err
+	}
+	monkey.Status.NextExperimentTime = &metav1.Time{Time: planned}
+	return true, nil
+}
+
+//GetInterval returns a random interval between the minimum and maximum intervals provided
+func GetInterval(minInterval, maxInterval string) (time.Duration, error) {
+	min, err := GetMinInterval(minInterval)
+	if err != nil {
+		return min, err
+	}
+	if min <= 0 {
+		return min, fmt.Errorf("interval must be positive, got %s", minInterval)
+	}
+	if maxInterval == "" {
+		return min, nil
+	}
+	max, err := time.ParseDuration(maxInterval)
+	if err != nil {
+		return min, err
+	}
+	if max < min {
+		return min, fmt.Errorf("maxInterval %s is less than interval %s", max, min)
+	}
+	return min + time.Duration(rand.Int63n(int64(max-min)+1)), nil
+}
+
+//GetRequeueInterval returns how long to wait before the next Chaos experiment of a monkey
+func GetRequeueInterval(monkey *podchaosv1alpha1.Monkey, now time.Time) (time.Duration, error) {
+	if next := monkey.Status.NextExperimentTime; next != nil && next.After(now) {
+		return next.Sub(now), nil
+	}
+	next, err := GetNextExperimentTime(monkey.Spec, now)
+	if err != nil {
+		return 0, err
+	}
+	return next.Sub(now), nil
+}
+
+//ReplanExperiment plans the next experiment of a monkey again from its last experiment when the spec changed since it
+//was planned, so a new interval or schedule applies straight away. It returns true when the plan changed
+func ReplanExperiment(monkey *podchaosv1alpha1.Monkey, now time.Time) (bool, error) {
+	if monkey.Status.ObservedGeneration == monkey.GetGeneration() {
+		return false, nil
+	}
+	monkey.Status.ObservedGeneration = monkey.GetGeneration()
+	if monkey.Status.NextExperimentTime == nil {
+		return true, nil
+	}
+	after := now
+	if last := monkey.Status.LastExperimentTime; last != nil {
+		after = last.Time
+	} else if start := monkey.Status.StartTime; start != nil {
+		after = start.Time
+	}
+	next, err := GetNextExperimentTime(monkey.Spec, after)
+	if err != nil {
+		return true, err
+	}
+	monkey.Status.NextExperimentTime = &metav1.Time{Time: next}
+	return true, nil
+}
+
+//claimExperiment records the experiment due at the time provided as run before any pod is deleted. The monkey is read
+//from the API server and the update is rejected when it changed since, so a reconcile working from a stale cache, or a
+//new leader after a failover, cannot run the same experiment twice. Only the experiment times are written, and the
+//monkey continues from the status read from the API server, so results recorded since the cache was filled are kept.
+//It returns false when the experiment must not run
+func (r *MonkeyReconciler) claimExperiment(ctx context.Context, monkey *podchaosv1alpha1.Monkey, now time.Time) (bool, error) {
+	stored := &podchaosv1alpha1.Monkey{}
+	if err := r.reader().Get(ctx, client.ObjectKeyFromObject(monkey), stored); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	if next := stored.Status.NextExperimentTime; next != nil && now.Before(next.Time) {
+		return false, nil
+	}
+	stored.Status.LastExperimentTime = &metav1.Time{Time: now}
+	stored.Status.NextExperimentTime = monkey.Status.NextExperimentTime
+	if err := r.Status().Update(ctx, stored); err != nil {
+		return false, err
+	}
+	monkey.Status = stored.Status
+	monkey.SetResourceVersion(stored.GetResourceVersion())
+	return true, nil
+}
+
+//GetActiveBlackoutWindow returns the blackout window the time provided falls within and when that window ends
+func GetActiveBlackoutWindow(spec podchaosv1alpha1.MonkeySpec, now time.Time) (*podchaosv1alpha1.BlackoutWindow, time.Time, error) {
+	for i := range spec.BlackoutWindows {
+		window := &spec.BlackoutWindows[i]
+		if window.Schedule == "" {
+			if window.Start == nil || window.End == nil {
+				return nil, time.Time{}, fmt.Errorf("blackout window %q requires start and end or schedule and duration", window.Name)
+			}
+			if !now.Before(window.Start.Time) && now.Before(window.End.Time) {
+				return window, window.End.Time, nil
+			}
+			continue
+		}
+		schedule, err := podchaosv1alpha1.ParseSchedule(window.Schedule, spec.TimeZone)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		duration, err := time.ParseDuration(window.Duration)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		// The most recent start of a recurring window covering now is the first activation after now-duration
+		if start := schedule.Next(now.Add(-duration)); !start.IsZero() && !start.After(now) {
+			return window, start.Add(duration), nil
+		}
+	}
+	return nil, time.Time{}, nil
+}
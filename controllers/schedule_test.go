@@ -0,0 +1,606 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	podchaosv1alpha1 "github.com/perithompson/podchaosmonkey/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestGetNextExperimentTime(t *testing.T) {
+	// Friday 2022-04-08 17:30 UTC
+	now := time.Date(2022, time.April, 8, 17, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		spec    podchaosv1alpha1.MonkeySpec
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name: "interval",
+			spec: podchaosv1alpha1.MonkeySpec{Interval: "5m"},
+			want: now.Add(5 * time.Minute),
+		},
+		{
+			name: "default interval",
+			spec: podchaosv1alpha1.MonkeySpec{},
+			want: now.Add(30 * time.Second),
+		},
+		{
+			name: "schedule takes precedence over interval",
+			spec: podchaosv1alpha1.MonkeySpec{Interval: "5m", Schedule: "0 * * * *"},
+			want: time.Date(2022, time.April, 8, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "weekday business hours skips the weekend",
+			spec: podchaosv1alpha1.MonkeySpec{Schedule: "*/15 9-16 * * 1-5"},
+			want: time.Date(2022, time.April, 11, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "time zone",
+			spec: podchaosv1alpha1.MonkeySpec{Schedule: "*/15 9-16 * * 1-5", TimeZone: "America/New_York"},
+			want: time.Date(2022, time.April, 8, 17, 45, 0, 0, time.UTC),
+		},
+		{
+			name:    "invalid schedule",
+			spec:    podchaosv1alpha1.MonkeySpec{Schedule: "every tuesday"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid time zone",
+			spec:    podchaosv1alpha1.MonkeySpec{Schedule: "0 * * * *", TimeZone: "Mars/Olympus_Mons"},
+			wantErr: true,
+		},
+		{
+			name:    "schedule never runs",
+			spec:    podchaosv1alpha1.MonkeySpec{Schedule: "0 0 30 2 *"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid interval",
+			spec:    podchaosv1alpha1.MonkeySpec{Interval: "often"},
+			wantErr: true,
+		},
+	}
+	g := NewWithT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetNextExperimentTime(tt.spec, now)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(got.Equal(tt.want)).Should(BeTrue(), "got %s want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetIntervalJitter(t *testing.T) {
+	tests := []struct {
+		name        string
+		minInterval string
+		maxInterval string
+		wantMin     time.Duration
+		wantMax     time.Duration
+		wantErr     bool
+	}{
+		{
+			name:        "no max interval",
+			minInterval: "1m",
+			wantMin:     time.Minute,
+			wantMax:     time.Minute,
+		},
+		{
+			name:        "between min and max",
+			minInterval: "1m",
+			maxInterval: "5m",
+			wantMin:     time.Minute,
+			wantMax:     5 * time.Minute,
+		},
+		{
+			name:        "default min interval",
+			maxInterval: "1m",
+			wantMin:     30 * time.Second,
+			wantMax:     time.Minute,
+		},
+		{
+			name:        "max equals min",
+			minInterval: "1m",
+			maxInterval: "60s",
+			wantMin:     time.Minute,
+			wantMax:     time.Minute,
+		},
+		{
+			name:        "max less than min",
+			minInterval: "5m",
+			maxInterval: "1m",
+			wantErr:     true,
+		},
+		{
+			name:        "zero min interval",
+			minInterval: "0s",
+			maxInterval: "1m",
+			wantErr:     true,
+		},
+		{
+			name:        "invalid max interval",
+			minInterval: "5m",
+			maxInterval: "later",
+			wantErr:     true,
+		},
+	}
+	g := NewWithT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen := map[time.Duration]bool{}
+			for i := 0; i < 50; i++ {
+				got, err := GetInterval(tt.minInterval, tt.maxInterval)
+				if tt.wantErr {
+					g.Expect(err).To(HaveOccurred())
+					return
+				}
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(got).Should(BeNumerically(">=", tt.wantMin))
+				g.Expect(got).Should(BeNumerically("<=", tt.wantMax))
+				seen[got] = true
+			}
+			if tt.wantMin != tt.wantMax {
+				g.Expect(len(seen)).Should(BeNumerically(">", 1))
+			}
+		})
+	}
+}
+
+func TestGetRequeueInterval(t *testing.T) {
+	now := time.Date(2022, time.April, 8, 17, 30, 0, 0, time.UTC)
+	scheduled := func(next *metav1.Time) *podchaosv1alpha1.Monkey {
+		monkey := Monkey("test", "5m", "workloads", false, map[string]string{}, []metav1.Condition{})
+		monkey.Spec.Schedule = "0 * * * *"
+		monkey.Status.NextExperimentTime = next
+		return monkey
+	}
+	g := NewWithT(t)
+
+	got, err := GetRequeueInterval(Monkey("test", "5m", "workloads", false, map[string]string{}, []metav1.Condition{}), now)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).Should(Equal(5 * time.Minute))
+
+	got, err = GetRequeueInterval(scheduled(nil), now)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).Should(Equal(30 * time.Minute))
+
+	got, err = GetRequeueInterval(scheduled(&metav1.Time{Time: now.Add(10 * time.Minute)}), now)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).Should(Equal(10 * time.Minute))
+
+	got, err = GetRequeueInterval(scheduled(&metav1.Time{Time: now.Add(-10 * time.Minute)}), now)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).Should(Equal(30 * time.Minute))
+}
+
+func TestMonkeyReconciler_PerformExperimentSchedule(t *testing.T) {
+	ctx := context.Background()
+	monkey := Monkey("scheduled", "", "workloads", false, map[string]string{"allowChaos": "true"}, []metav1.Condition{})
+	monkey.Spec.Schedule = "*/5 * * * *"
+	monkey.Status.NextExperimentTime = &metav1.Time{Time: time.Now().Add(time.Hour)}
+	pod := Pod("delete", "1", "workloads", "true")
+	c, fakeScheme := InitTests(t, monkey.DeepCopy(), &pod)
+	g := NewWithT(t)
+	r := &MonkeyReconciler{
+		Client: c,
+		Scheme: fakeScheme,
+	}
+
+	// waiting when the next experiment is in the future
+	got, err := r.PerformExperiment(ctx, monkey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.RequeueAfter).Should(BeNumerically(">", 59*time.Minute))
+	pods := &corev1.PodList{}
+	g.Expect(r.List(ctx, pods)).To(Succeed())
+	g.Expect(pods.Items).Should(HaveLen(1))
+
+	// running once the next experiment is due
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(monkey), monkey)).To(Succeed())
+	monkey.Status.NextExperimentTime = &metav1.Time{Time: time.Now().Add(-time.Second)}
+	g.Expect(r.Status().Update(ctx, monkey)).To(Succeed())
+	got, err = r.PerformExperiment(ctx, monkey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.RequeueAfter).Should(BeNumerically("<=", 5*time.Minute))
+	g.Expect(r.List(ctx, pods)).To(Succeed())
+	g.Expect(pods.Items).Should(BeEmpty())
+
+	// recording the next planned run
+	stored := &podchaosv1alpha1.Monkey{}
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(monkey), stored)).To(Succeed())
+	g.Expect(stored.Status.NextExperimentTime).ShouldNot(BeNil())
+	g.Expect(stored.Status.NextExperimentTime.Time).Should(BeTemporally("~", time.Now().Add(got.RequeueAfter), time.Second))
+}
+
+func TestMonkeyReconciler_PerformExperimentMissedSchedule(t *testing.T) {
+	ctx := context.Background()
+	monkey := Monkey("yearly", "", "workloads", false, map[string]string{"allowChaos": "true"}, []metav1.Condition{})
+	monkey.Spec.Schedule = "0 3 1 1 *"
+	monkey.Status.StartTime = &metav1.Time{Time: time.Now().Add(-48 * time.Hour)}
+	// the planned experiment was missed, e.g. while the controller was down
+	monkey.Status.NextExperimentTime = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+	pod := Pod("survivor", "1", "workloads", "true")
+	c, fakeScheme := InitTests(t, monkey.DeepCopy(), &pod)
+	g := NewWithT(t)
+	r := &MonkeyReconciler{
+		Client: c,
+		Scheme: fakeScheme,
+	}
+
+	got, err := r.PerformExperiment(ctx, monkey)
+	g.Expect(err).ToNot(HaveOccurred())
+	schedule, err := podchaosv1alpha1.ParseSchedule(monkey.Spec.Schedule, "")
+	g.Expect(err).ToNot(HaveOccurred())
+	want := schedule.Next(time.Now())
+	g.Expect(got.RequeueAfter).Should(BeNumerically("~", time.Until(want), time.Second))
+	pods := &corev1.PodList{}
+	g.Expect(r.List(ctx, pods)).To(Succeed())
+	g.Expect(pods.Items).Should(HaveLen(1))
+
+	// the next slot of the schedule is planned without running the missed experiment
+	stored := &podchaosv1alpha1.Monkey{}
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(monkey), stored)).To(Succeed())
+	g.Expect(stored.Status.NextExperimentTime.Time).Should(BeTemporally("==", want))
+	g.Expect(stored.Status.LastExperimentTime).Should(BeNil())
+	g.Expect(stored.Status.TotalKills).Should(BeZero())
+}
+
+func TestMonkeyReconciler_PerformExperimentJitter(t *testing.T) {
+	ctx := context.Background()
+	monkey := Monkey("jittered", "1m", "workloads", true, map[string]string{"allowChaos": "true"}, []metav1.Condition{})
+	monkey.Spec.MaxInterval = "10m"
+	c, fakeScheme := InitTests(t, monkey.DeepCopy())
+	g := NewWithT(t)
+	r := &MonkeyReconciler{
+		Client: c,
+		Scheme: fakeScheme,
+	}
+
+	got, err := r.PerformExperiment(ctx, monkey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.RequeueAfter).Should(BeNumerically(">=", time.Minute))
+	g.Expect(got.RequeueAfter).Should(BeNumerically("<=", 10*time.Minute))
+
+	// exposing the chosen next kill time
+	stored := &podchaosv1alpha1.Monkey{}
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(monkey), stored)).To(Succeed())
+	g.Expect(stored.Status.NextExperimentTime).ShouldNot(BeNil())
+	g.Expect(stored.Status.NextExperimentTime.Time).Should(BeTemporally("~", time.Now().Add(got.RequeueAfter), time.Second))
+
+	// a reconcile before the chosen time waits for it rather than running again
+	again, err := r.PerformExperiment(ctx, stored)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(again.RequeueAfter).Should(BeNumerically("~", got.RequeueAfter, time.Second))
+}
+
+func TestMonkeyReconciler_ReconcileCadence(t *testing.T) {
+	ctx := context.Background()
+	monkey := Monkey("cadence", "5m", "workloads", false, map[string]string{"allowChaos": "true"}, nil)
+	first := Pod("first", "1", "workloads", "true")
+	second := Pod("second", "2", "workloads", "true")
+	c, fakeScheme := InitTests(t, monkey, &first, &second)
+	g := NewWithT(t)
+	r := &MonkeyReconciler{
+		Client: c,
+		Scheme: fakeScheme,
+	}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(monkey)}
+	pods := &corev1.PodList{}
+
+	// registering and reconciling again before the next experiment is due leaves every pod alone
+	for i := 0; i < 3; i++ {
+		_, err := r.Reconcile(ctx, req)
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+	g.Expect(r.List(ctx, pods)).To(Succeed())
+	g.Expect(pods.Items).Should(HaveLen(2))
+
+	// the experiment runs once when it is due, however many reconciles follow
+	stored := &podchaosv1alpha1.Monkey{}
+	g.Expect(r.Get(ctx, req.NamespacedName, stored)).To(Succeed())
+	stored.Status.NextExperimentTime = &metav1.Time{Time: time.Now().Add(-time.Second)}
+	g.Expect(r.Status().Update(ctx, stored)).To(Succeed())
+	for i := 0; i < 3; i++ {
+		_, err := r.Reconcile(ctx, req)
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+	g.Expect(r.List(ctx, pods)).To(Succeed())
+	g.Expect(pods.Items).Should(HaveLen(1))
+	g.Expect(r.Get(ctx, req.NamespacedName, stored)).To(Succeed())
+	g.Expect(stored.Status.LastExperimentTime).ShouldNot(BeNil())
+	g.Expect(stored.Status.LastExperimentTime.Time).Should(BeTemporally("~", time.Now(), time.Second))
+	g.Expect(stored.Status.NextExperimentTime.Time).Should(BeTemporally("~", time.Now().Add(5*time.Minute), time.Second))
+
+	// a reconcile working from a copy read before the experiment ran does not run it again
+	stale := stored.DeepCopy()
+	stale.Status.NextExperimentTime = &metav1.Time{Time: time.Now().Add(-time.Second)}
+	stale.Status.LastExperimentTime = nil
+	_, err := r.PerformExperiment(ctx, stale)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.List(ctx, pods)).To(Succeed())
+	g.Expect(pods.Items).Should(HaveLen(1))
+}
+
+// staleCache reads Monkeys as they were when it was created, like an informer cache that has not seen later writes
+type staleCache struct {
+	client.WithWatch
+	monkeys map[client.ObjectKey]*podchaosv1alpha1.Monkey
+}
+
+func (c *staleCache) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	if monkey, ok := c.monkeys[key]; ok {
+		if out, ok := obj.(*podchaosv1alpha1.Monkey); ok {
+			monkey.DeepCopyInto(out)
+			return nil
+		}
+	}
+	return c.WithWatch.Get(ctx, key, obj)
+}
+
+func TestMonkeyReconciler_PerformExperimentStaleCache(t *testing.T) {
+	ctx := context.Background()
+	monkey := Monkey("stale", "5m", "workloads", false, map[string]string{"allowChaos": "true"}, []metav1.Condition{})
+	monkey.Spec.RecoveryTimeout = "1m"
+	pod := ReplicaPod("victim", "1", "workloads", "ReplicaSet", "web", true)
+	pod.Labels = map[string]string{"allowChaos": "true"}
+	c, fakeScheme := InitTests(t, monkey, pod, ReplicaSet("web", "workloads", 1))
+	g := NewWithT(t)
+	cached := &podchaosv1alpha1.Monkey{}
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(monkey), cached)).To(Succeed())
+	r := &MonkeyReconciler{
+		Client:    &staleCache{WithWatch: c, monkeys: map[client.ObjectKey]*podchaosv1alpha1.Monkey{client.ObjectKeyFromObject(monkey): cached}},
+		Scheme:    fakeScheme,
+		APIReader: c,
+	}
+	// results recorded by an earlier reconcile that the cache has not seen yet
+	stored := cached.DeepCopy()
+	stored.Status.TotalKills = 5
+	stored.Status.LastRecovery = &podchaosv1alpha1.RecoveryCheck{Namespace: "workloads", PodName: "earlier", Result: podchaosv1alpha1.RecoveryPassed}
+	g.Expect(c.Status().Update(ctx, stored)).To(Succeed())
+
+	// the status written after the claim keeps the results of the experiment and those recorded before it, although
+	// the cache still holds the status and resourceVersion from before both
+	_, err := r.PerformExperiment(ctx, cached.DeepCopy())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(monkey), stored)).To(Succeed())
+	g.Expect(stored.ResourceVersion).ShouldNot(Equal(cached.ResourceVersion))
+	g.Expect(stored.Status.LastExperimentTime).ShouldNot(BeNil())
+	g.Expect(stored.Status.TotalKills).Should(Equal(int64(6)))
+	g.Expect(stored.Status.LastRecovery).ShouldNot(BeNil())
+	g.Expect(stored.Status.LastRecovery.PodName).Should(Equal("earlier"))
+	g.Expect(stored.Status.History).Should(HaveLen(1))
+	g.Expect(stored.Status.LastVictims).Should(HaveLen(1))
+	g.Expect(stored.Status.PendingRecoveries).Should(HaveLen(1))
+
+	// a reconcile working from the stale cache does not run the claimed experiment again
+	_, err = r.PerformExperiment(ctx, cached.DeepCopy())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(monkey), stored)).To(Succeed())
+	g.Expect(stored.Status.TotalKills).Should(Equal(int64(6)))
+	g.Expect(stored.Status.History).Should(HaveLen(1))
+}
+
+func TestMonkeyReconciler_claimExperiment(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	monkey := Monkey("claim", "5m", "workloads", false, map[string]string{"allowChaos": "true"}, []metav1.Condition{})
+	c, fakeScheme := InitTests(t, monkey)
+	g := NewWithT(t)
+	cached := &podchaosv1alpha1.Monkey{}
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(monkey), cached)).To(Succeed())
+	r := &MonkeyReconciler{
+		Client:    c,
+		Scheme:    fakeScheme,
+		APIReader: c,
+	}
+	stored := cached.DeepCopy()
+	stored.Status.TotalKills = 3
+	stored.Status.ConsecutiveRecoveryFailures = 1
+	g.Expect(c.Status().Update(ctx, stored)).To(Succeed())
+
+	// only the experiment times of the cached copy are written over the status read from the API server
+	cached.Status.NextExperimentTime = &metav1.Time{Time: now.Add(5 * time.Minute)}
+	claimed, err := r.claimExperiment(ctx, cached, now)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(claimed).Should(BeTrue())
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(monkey), stored)).To(Succeed())
+	g.Expect(stored.Status.LastExperimentTime.Time).Should(BeTemporally("~", now, time.Second))
+	g.Expect(stored.Status.NextExperimentTime.Time).Should(BeTemporally("~", now.Add(5*time.Minute), time.Second))
+	g.Expect(stored.Status.TotalKills).Should(Equal(int64(3)))
+	g.Expect(stored.Status.ConsecutiveRecoveryFailures).Should(Equal(int32(1)))
+	g.Expect(cached.Status.TotalKills).Should(Equal(int64(3)))
+	g.Expect(cached.Status.ConsecutiveRecoveryFailures).Should(Equal(int32(1)))
+	g.Expect(cached.ResourceVersion).Should(Equal(stored.ResourceVersion))
+
+	// the experiment is not claimed again before the next one is due
+	claimed, err = r.claimExperiment(ctx, cached, now)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(claimed).Should(BeFalse())
+}
+
+func TestReplanExperiment(t *testing.T) {
+	now := time.Date(2022, time.April, 8, 17, 30, 0, 0, time.UTC)
+	last := &metav1.Time{Time: now.Add(-10 * time.Minute)}
+	tests := []struct {
+		name        string
+		generation  int64
+		observed    int64
+		interval    string
+		last        *metav1.Time
+		next        *metav1.Time
+		wantChanged bool
+		want        *metav1.Time
+	}{
+		{
+			name:       "unchanged spec keeps the plan",
+			generation: 2,
+			observed:   2,
+			interval:   "1h",
+			last:       last,
+			next:       &metav1.Time{Time: now.Add(50 * time.Minute)},
+			want:       &metav1.Time{Time: now.Add(50 * time.Minute)},
+		},
+		{
+			name:        "shorter interval plans from the last experiment",
+			generation:  3,
+			observed:    2,
+			interval:    "15m",
+			last:        last,
+			next:        &metav1.Time{Time: now.Add(50 * time.Minute)},
+			wantChanged: true,
+			want:        &metav1.Time{Time: now.Add(5 * time.Minute)},
+		},
+		{
+			name:        "suspended monkey stays unplanned",
+			generation:  3,
+			observed:    2,
+			interval:    "15m",
+			last:        last,
+			wantChanged: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			monkey := Monkey("replan", tt.interval, "workloads", true, nil, nil)
+			monkey.SetGeneration(tt.generation)
+			monkey.Status.ObservedGeneration = tt.observed
+			monkey.Status.LastExperimentTime = tt.last
+			monkey.Status.NextExperimentTime = tt.next
+			changed, err := ReplanExperiment(monkey, now)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(changed).Should(Equal(tt.wantChanged))
+			g.Expect(monkey.Status.ObservedGeneration).Should(Equal(tt.generation))
+			g.Expect(monkey.Status.NextExperimentTime).Should(Equal(tt.want))
+		})
+	}
+}
+
+func TestGetActiveBlackoutWindow(t *testing.T) {
+	// Friday 2022-04-08 17:30 UTC
+	now := time.Date(2022, time.April, 8, 17, 30, 0, 0, time.UTC)
+	absolute := podchaosv1alpha1.BlackoutWindow{
+		Name:  "easter",
+		Start: &metav1.Time{Time: time.Date(2022, time.April, 14, 0, 0, 0, 0, time.UTC)},
+		End:   &metav1.Time{Time: time.Date(2022, time.April, 19, 0, 0, 0, 0, time.UTC)},
+	}
+	evenings := podchaosv1alpha1.BlackoutWindow{
+		Name:     "evenings",
+		Schedule: "0 17 * * *",
+		Duration: "15h",
+	}
+	tests := []struct {
+		name       string
+		spec       podchaosv1alpha1.MonkeySpec
+		now        time.Time
+		wantWindow string
+		wantEnd    time.Time
+		wantErr    bool
+	}{
+		{
+			name: "no windows",
+			spec: podchaosv1alpha1.MonkeySpec{},
+			now:  now,
+		},
+		{
+			name: "before absolute window",
+			spec: podchaosv1alpha1.MonkeySpec{BlackoutWindows: []podchaosv1alpha1.BlackoutWindow{absolute}},
+			now:  now,
+		},
+		{
+			name:       "within absolute window",
+			spec:       podchaosv1alpha1.MonkeySpec{BlackoutWindows: []podchaosv1alpha1.BlackoutWindow{absolute}},
+			now:        absolute.Start.Time,
+			wantWindow: "easter",
+			wantEnd:    absolute.End.Time,
+		},
+		{
+			name: "after absolute window",
+			spec: podchaosv1alpha1.MonkeySpec{BlackoutWindows: []podchaosv1alpha1.BlackoutWindow{absolute}},
+			now:  absolute.End.Time,
+		},
+		{
+			name:       "within recurring window",
+			spec:       podchaosv1alpha1.MonkeySpec{BlackoutWindows: []podchaosv1alpha1.BlackoutWindow{absolute, evenings}},
+			now:        now,
+			wantWindow: "evenings",
+			wantEnd:    time.Date(2022, time.April, 9, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "within recurring window started the previous day",
+			spec:       podchaosv1alpha1.MonkeySpec{BlackoutWindows: []podchaosv1alpha1.BlackoutWindow{evenings}},
+			now:        time.Date(2022, time.April, 8, 7, 59, 0, 0, time.UTC),
+			wantWindow: "evenings",
+			wantEnd:    time.Date(2022, time.April, 8, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "outside recurring window",
+			spec: podchaosv1alpha1.MonkeySpec{BlackoutWindows: []podchaosv1alpha1.BlackoutWindow{evenings}},
+			now:  time.Date(2022, time.April, 8, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "recurring window in time zone",
+			spec:       podchaosv1alpha1.MonkeySpec{TimeZone: "America/New_York", BlackoutWindows: []podchaosv1alpha1.BlackoutWindow{evenings}},
+			now:        time.Date(2022, time.April, 8, 21, 0, 0, 0, time.UTC),
+			wantWindow: "evenings",
+			wantEnd:    time.Date(2022, time.April, 9, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "incomplete absolute window",
+			spec:    podchaosv1alpha1.MonkeySpec{BlackoutWindows: []podchaosv1alpha1.BlackoutWindow{{Name: "open", Start: absolute.Start}}},
+			now:     now,
+			wantErr: true,
+		},
+		{
+			name:    "recurring window without duration",
+			spec:    podchaosv1alpha1.MonkeySpec{BlackoutWindows: []podchaosv1alpha1.BlackoutWindow{{Name: "nightly", Schedule: "0 0 * * *"}}},
+			now:     now,
+			wantErr: true,
+		},
+	}
+	g := NewWithT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, end, err := GetActiveBlackoutWindow(tt.spec, tt.now)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			if tt.wantWindow == "" {
+				g.Expect(window).Should(BeNil())
+				return
+			}
+			g.Expect(window).ShouldNot(BeNil())
+			g.Expect(window.Name).Should(Equal(tt.wantWindow))
+			g.Expect(end.Equal(tt.wantEnd)).Should(BeTrue(), "got %s want %s", end, tt.wantEnd)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	podchaosv1alpha1 "github.com/perithompson/podchaosmonkey/api/v1alpha1"
+)
+
+//Suspend marks a monkey with suspend set as suspended without requeuing it, the update that unsets suspend triggers
+//the next reconcile
+func (r *MonkeyReconciler) Suspend(ctx context.Context, monkey *podchaosv1alpha1.Monkey) (ctrl.Result, error) {
+	message := "Chaos suspended by spec.suspend"
+	if !setCondition(monkey, podchaosv1alpha1.ConditionSuspended, metav1.ConditionTrue, podchaosv1alpha1.ReasonSuspendedBySpec, message) {
+		return ctrl.Result{}, nil
+	}
+	ctrl.Log.WithName("controller").WithName("monkey").Info(fmt.Sprintf("Suspended monkey: %s", client.ObjectKeyFromObject(monkey)))
+	r.recordEvent(monkey, corev1.EventTypeNormal, EventReasonSuspended, message)
+	monkey.Status.NextExperimentTime = nil
+	_, err := r.UpdateStatus(ctx, monkey)
+	return ctrl.Result{}, err
+}
+
+//ResumeFromSuspend marks a monkey that was suspended by spec.suspend as active again, planning its next experiment
+//from the time provided rather than running one straight away. It returns true when the monkey was resumed
+func (r *MonkeyReconciler) ResumeFromSuspend(ctx context.Context, monkey *podchaosv1alpha1.Monkey, now time.Time) (bool, error) {
+	suspended := meta.FindStatusCondition(monkey.Status.Conditions, podchaosv1alpha1.ConditionSuspended)
+	if suspended == nil || suspended.Status != metav1.ConditionTrue || suspended.Reason != podchaosv1alpha1.ReasonSuspendedBySpec {
+		return false, nil
+	}
+	next, err := GetNextExperimentTime(monkey.Spec, now)
+	if err != nil {
+		return false, err
+	}
+	clearCondition(monkey, podchaosv1alpha1.ConditionSuspended, podchaosv1alpha1.ReasonActive)
+	monkey.Status.NextExperimentTime = &metav1.Time{Time: next}
+	ctrl.Log.WithName("controller").WithName("monkey").Info(fmt.Sprintf("Resumed monkey: %s", client.ObjectKeyFromObject(monkey)))
+	r.recordEvent(monkey, corev1.EventTypeNormal, EventReasonResumed, "Resumed after spec.suspend was unset")
+	_, err = r.UpdateStatus(ctx, monkey)
+	return true, err
+}
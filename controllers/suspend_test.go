@@ -0,0 +1,70 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	podchaosv1alpha1 "github.com/perithompson/podchaosmonkey/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestMonkeyReconciler_PerformExperimentSuspend(t *testing.T) {
+	ctx := context.Background()
+	g := NewWithT(t)
+	monkey := Monkey("suspend", "5m", "workloads", false, map[string]string{"allowChaos": "true"}, []metav1.Condition{})
+	monkey.Spec.Suspend = true
+	monkey.Status.NextExperimentTime = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+	pod := Pod("delete", "1", "workloads", "true")
+	c, fakeScheme := InitTests(t, monkey.DeepCopy(), &pod)
+	r := &MonkeyReconciler{
+		Client: c,
+		Scheme: fakeScheme,
+	}
+
+	result, err := r.PerformExperiment(ctx, monkey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.RequeueAfter).Should(BeZero())
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(&pod), &corev1.Pod{})).To(Succeed())
+
+	stored := &podchaosv1alpha1.Monkey{}
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(monkey), stored)).To(Succeed())
+	suspended := meta.FindStatusCondition(stored.Status.Conditions, podchaosv1alpha1.ConditionSuspended)
+	g.Expect(suspended).ShouldNot(BeNil())
+	g.Expect(suspended.Status).Should(Equal(metav1.ConditionTrue))
+	g.Expect(suspended.Reason).Should(Equal(podchaosv1alpha1.ReasonSuspendedBySpec))
+	g.Expect(stored.Status.NextExperimentTime).Should(BeNil())
+
+	// Resuming plans the next experiment from now rather than deleting a pod straight away
+	stored.Spec.Suspend = false
+	result, err = r.PerformExperiment(ctx, stored)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.RequeueAfter).Should(BeNumerically("~", 5*time.Minute, time.Second))
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(&pod), &corev1.Pod{})).To(Succeed())
+
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(monkey), stored)).To(Succeed())
+	suspended = meta.FindStatusCondition(stored.Status.Conditions, podchaosv1alpha1.ConditionSuspended)
+	g.Expect(suspended.Status).Should(Equal(metav1.ConditionFalse))
+	g.Expect(suspended.Reason).Should(Equal(podchaosv1alpha1.ReasonActive))
+	g.Expect(stored.Status.NextExperimentTime).ShouldNot(BeNil())
+}
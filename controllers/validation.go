@@ -0,0 +1,87 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	podchaosv1alpha1 "github.com/perithompson/podchaosmonkey/api/v1alpha1"
+)
+
+//specFieldReasons maps the top level spec fields to the reason reported when they are invalid
+var specFieldReasons = map[string]string{
+	"interval":          podchaosv1alpha1.ReasonInvalidInterval,
+	"maxInterval":       podchaosv1alpha1.ReasonInvalidInterval,
+	"schedule":          podchaosv1alpha1.ReasonInvalidSchedule,
+	"timeZone":          podchaosv1alpha1.ReasonInvalidSchedule,
+	"blackoutWindows":   podchaosv1alpha1.ReasonInvalidSchedule,
+	"selector":          podchaosv1alpha1.ReasonInvalidSelector,
+	"excludeSelector":   podchaosv1alpha1.ReasonInvalidSelector,
+	"namespaceSelector": podchaosv1alpha1.ReasonInvalidSelector,
+	"candidates":        podchaosv1alpha1.ReasonInvalidSelector,
+	"mode":              podchaosv1alpha1.ReasonInvalidMode,
+	"value":             podchaosv1alpha1.ReasonInvalidMode,
+	"recoveryTimeout":   podchaosv1alpha1.ReasonInvalidDuration,
+	"duration":          podchaosv1alpha1.ReasonInvalidDuration,
+}
+
+//ValidateSpec checks that a monkey's spec can be run with the validation shared with the admission webhook, returning
+//the reason and error of the first problem found. The webhook may be disabled or the Monkey may predate it
+func ValidateSpec(spec podchaosv1alpha1.MonkeySpec) (string, error) {
+	specPath := field.NewPath("spec")
+	allErrs := podchaosv1alpha1.ValidateMonkeySpec(&spec, specPath)
+	if len(allErrs) == 0 {
+		return "", nil
+	}
+	specErr := allErrs[0]
+	// The top level field is the first segment after "spec.", e.g. blackoutWindows in spec.blackoutWindows[0].duration
+	name := strings.TrimPrefix(specErr.Field, specPath.String()+".")
+	if i := strings.IndexAny(name, ".["); i >= 0 {
+		name = name[:i]
+	}
+	return specFieldReasons[name], specErr
+}
+
+//CheckSpec sets the InvalidSpec condition of a monkey whose spec cannot be run, returning true when it is invalid.
+//An invalid monkey is not requeued, the update that fixes its spec triggers the next reconcile
+func (r *MonkeyReconciler) CheckSpec(ctx context.Context, monkey *podchaosv1alpha1.Monkey) (bool, error) {
+	reason, specErr := ValidateSpec(monkey.Spec)
+	if specErr == nil {
+		clearCondition(monkey, podchaosv1alpha1.ConditionInvalidSpec, podchaosv1alpha1.ReasonValid)
+		return false, nil
+	}
+	changed := setCondition(monkey, podchaosv1alpha1.ConditionInvalidSpec, metav1.ConditionTrue, reason, specErr.Error())
+	if changed {
+		ctrl.Log.WithName("controller").WithName("monkey").Info(fmt.Sprintf("Invalid spec for monkey %s: %v", client.ObjectKeyFromObject(monkey), specErr))
+		r.recordEvent(monkey, corev1.EventTypeWarning, EventReasonInvalidSpec, specErr.Error())
+	}
+	if changed || monkey.Status.ObservedGeneration != monkey.GetGeneration() {
+		monkey.Status.ObservedGeneration = monkey.GetGeneration()
+		if _, err := r.UpdateStatus(ctx, monkey); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	podchaosv1alpha1 "github.com/perithompson/podchaosmonkey/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestValidateSpec(t *testing.T) {
+	valid := podchaosv1alpha1.MonkeySpec{
+		Interval: "5m",
+		Selector: metav1.LabelSelector{MatchLabels: map[string]string{"allowChaos": "true"}},
+	}
+	tests := []struct {
+		name       string
+		mutate     func(spec *podchaosv1alpha1.MonkeySpec)
+		wantReason string
+	}{
+		{name: "valid", mutate: func(spec *podchaosv1alpha1.MonkeySpec) {}},
+		{name: "unparseable interval", mutate: func(spec *podchaosv1alpha1.MonkeySpec) { spec.Interval = "often" }, wantReason: podchaosv1alpha1.ReasonInvalidInterval},
+		{name: "zero interval", mutate: func(spec *podchaosv1alpha1.MonkeySpec) { spec.Interval = "0s" }, wantReason: podchaosv1alpha1.ReasonInvalidInterval},
+		{name: "negative interval", mutate: func(spec *podchaosv1alpha1.MonkeySpec) { spec.Interval = "-5m" }, wantReason: podchaosv1alpha1.ReasonInvalidInterval},
+		{name: "negative maxInterval", mutate: func(spec *podchaosv1alpha1.MonkeySpec) { spec.MaxInterval = "-10m" }, wantReason: podchaosv1alpha1.ReasonInvalidInterval},
+		{name: "maxInterval below interval", mutate: func(spec *podchaosv1alpha1.MonkeySpec) { spec.MaxInterval = "1m" }, wantReason: podchaosv1alpha1.ReasonInvalidInterval},
+		{name: "unparseable schedule", mutate: func(spec *podchaosv1alpha1.MonkeySpec) { spec.Schedule = "every day" }, wantReason: podchaosv1alpha1.ReasonInvalidSchedule},
+		{
+			name: "unparseable blackout window",
+			mutate: func(spec *podchaosv1alpha1.MonkeySpec) {
+				spec.BlackoutWindows = []podchaosv1alpha1.BlackoutWindow{{Name: "nights", Schedule: "0 22 * * *", Duration: "all night"}}
+			},
+			wantReason: podchaosv1alpha1.ReasonInvalidSchedule,
+		},
+		{
+			name: "blackout window ending before it starts",
+			mutate: func(spec *podchaosv1alpha1.MonkeySpec) {
+				start := metav1.NewTime(time.Date(2022, time.December, 24, 0, 0, 0, 0, time.UTC))
+				end := metav1.NewTime(start.Add(-time.Hour))
+				spec.BlackoutWindows = []podchaosv1alpha1.BlackoutWindow{{Name: "christmas", Start: &start, End: &end}}
+			},
+			wantReason: podchaosv1alpha1.ReasonInvalidSchedule,
+		},
+		{
+			name: "blackout window without a positive duration",
+			mutate: func(spec *podchaosv1alpha1.MonkeySpec) {
+				spec.BlackoutWindows = []podchaosv1alpha1.BlackoutWindow{{Name: "nights", Schedule: "0 22 * * *", Duration: "0s"}}
+			},
+			wantReason: podchaosv1alpha1.ReasonInvalidSchedule,
+		},
+		{
+			name: "invalid blackout window after an active one",
+			mutate: func(spec *podchaosv1alpha1.MonkeySpec) {
+				start := metav1.NewTime(time.Now().Add(-time.Hour))
+				end := metav1.NewTime(time.Now().Add(time.Hour))
+				spec.BlackoutWindows = []podchaosv1alpha1.BlackoutWindow{
+					{Name: "now", Start: &start, End: &end},
+					{Name: "nights", Schedule: "at night", Duration: "8h"},
+				}
+			},
+			wantReason: podchaosv1alpha1.ReasonInvalidSchedule,
+		},
+		{
+			name: "invalid selector operator",
+			mutate: func(spec *podchaosv1alpha1.MonkeySpec) {
+				spec.Selector.MatchExpressions = []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Near", Values: []string{"web"}}}
+			},
+			wantReason: podchaosv1alpha1.ReasonInvalidSelector,
+		},
+		{
+			name: "invalid namespace selector",
+			mutate: func(spec *podchaosv1alpha1.MonkeySpec) {
+				spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"chaos/": "true"}}
+			},
+			wantReason: podchaosv1alpha1.ReasonInvalidSelector,
+		},
+		{
+			name: "invalid exclude selector",
+			mutate: func(spec *podchaosv1alpha1.MonkeySpec) {
+				spec.ExcludeSelector = &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "role", Operator: metav1.LabelSelectorOpIn}}}
+			},
+			wantReason: podchaosv1alpha1.ReasonInvalidSelector,
+		},
+		{name: "percent without a value", mutate: func(spec *podchaosv1alpha1.MonkeySpec) { spec.Mode = podchaosv1alpha1.KillModePercent }, wantReason: podchaosv1alpha1.ReasonInvalidMode},
+		{name: "unknown mode", mutate: func(spec *podchaosv1alpha1.MonkeySpec) { spec.Mode = "some" }, wantReason: podchaosv1alpha1.ReasonInvalidMode},
+		{
+			name: "unknown candidate phase",
+			mutate: func(spec *podchaosv1alpha1.MonkeySpec) {
+				spec.Candidates = &podchaosv1alpha1.CandidatePolicy{Phases: []corev1.PodPhase{"Crashing"}}
+			},
+			wantReason: podchaosv1alpha1.ReasonInvalidSelector,
+		},
+		{name: "negative recovery timeout", mutate: func(spec *podchaosv1alpha1.MonkeySpec) { spec.RecoveryTimeout = "-1m" }, wantReason: podchaosv1alpha1.ReasonInvalidDuration},
+		{name: "unparseable duration", mutate: func(spec *podchaosv1alpha1.MonkeySpec) { spec.Duration = "a week" }, wantReason: podchaosv1alpha1.ReasonInvalidDuration},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			spec := *valid.DeepCopy()
+			tt.mutate(&spec)
+			reason, err := ValidateSpec(spec)
+			g.Expect(reason).Should(Equal(tt.wantReason))
+			if tt.wantReason == "" {
+				g.Expect(err).ToNot(HaveOccurred())
+			} else {
+				g.Expect(err).To(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestMonkeyReconciler_ReconcileInvalidSpec(t *testing.T) {
+	ctx := context.Background()
+	monkey := Monkey("invalid", "often", "workloads", false, map[string]string{"allowChaos": "true"}, nil)
+	monkey.SetGeneration(1)
+	pod := Pod("survivor", "1", "workloads", "true")
+	c, fakeScheme := InitTests(t, monkey, &pod)
+	g := NewWithT(t)
+	r := &MonkeyReconciler{
+		Client: c,
+		Scheme: fakeScheme,
+	}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(monkey)}
+
+	// an invalid spec is reported on the monkey rather than retried
+	for i := 0; i < 2; i++ {
+		got, err := r.Reconcile(ctx, req)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).Should(Equal(ctrl.Result{}))
+	}
+	stored := &podchaosv1alpha1.Monkey{}
+	g.Expect(r.Get(ctx, req.NamespacedName, stored)).To(Succeed())
+	invalid := meta.FindStatusCondition(stored.Status.Conditions, podchaosv1alpha1.ConditionInvalidSpec)
+	g.Expect(invalid).ShouldNot(BeNil())
+	g.Expect(invalid.Status).Should(Equal(metav1.ConditionTrue))
+	g.Expect(invalid.Reason).Should(Equal(podchaosv1alpha1.ReasonInvalidInterval))
+	g.Expect(invalid.Message).Should(ContainSubstring("often"))
+	g.Expect(meta.IsStatusConditionFalse(stored.Status.Conditions, podchaosv1alpha1.ConditionReady)).Should(BeTrue())
+	g.Expect(meta.IsStatusConditionFalse(stored.Status.Conditions, podchaosv1alpha1.ConditionActive)).Should(BeTrue())
+
+	// fixing the spec registers the monkey without deleting pods straight away
+	stored.Spec.Interval = "5m"
+	stored.SetGeneration(2)
+	g.Expect(r.Update(ctx, stored)).To(Succeed())
+	got, err := r.Reconcile(ctx, req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.RequeueAfter).Should(BeNumerically(">", 0))
+	g.Expect(r.Get(ctx, req.NamespacedName, stored)).To(Succeed())
+	g.Expect(meta.IsStatusConditionFalse(stored.Status.Conditions, podchaosv1alpha1.ConditionInvalidSpec)).Should(BeTrue())
+	g.Expect(meta.IsStatusConditionTrue(stored.Status.Conditions, podchaosv1alpha1.ConditionReady)).Should(BeTrue())
+	g.Expect(stored.Status.NextExperimentTime).ShouldNot(BeNil())
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(&pod), &pod)).To(Succeed())
+}
@@ -0,0 +1,67 @@
+#!/usr/bin/env bash
+# Generates a Role and RoleBinding granting the manager the rules of its ClusterRole in each namespace it watches and
+# in its own namespace, together with the patch that restricts the manager to the watched namespaces.
+#
+# Usage: hack/namespaced-rbac.sh <comma separated namespaces> <manager namespace> <cluster role> <output directory>
+set -o errexit -o nounset -o pipefail
+
+namespaces="$1"
+manager_namespace="$2"
+cluster_role="$3"
+output="$4"
+prefix="podchaosmonkey-"
+
+rules="$(sed -n '/^rules:/,$p' "${cluster_role}")"
+
+role_namespaces="${manager_namespace}"
+for namespace in ${namespaces//,/ }; do
+  if [[ "${namespace}" != "${manager_namespace}" ]]; then
+    role_namespaces="${role_namespaces} ${namespace}"
+  fi
+done
+
+{
+  echo "# Code generated by hack/namespaced-rbac.sh. DO NOT EDIT."
+  for namespace in ${role_namespaces}; do
+    cat <<YAML
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: ${prefix}manager-role
+  namespace: ${namespace}
+${rules}
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: ${prefix}manager-rolebinding
+  namespace: ${namespace}
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: ${prefix}manager-role
+subjects:
+- kind: ServiceAccount
+  name: ${prefix}controller-manager
+  namespace: ${manager_namespace}
+YAML
+  done
+} > "${output}/role.yaml"
+
+cat > "${output}/manager_watch_namespaces_patch.yaml" <<YAML
+# Code generated by hack/namespaced-rbac.sh. DO NOT EDIT.
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: ${prefix}controller-manager
+  namespace: ${manager_namespace}
+spec:
+  template:
+    spec:
+      containers:
+      - name: manager
+        env:
+        - name: WATCH_NAMESPACES
+          value: ${namespaces}
+YAML
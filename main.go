@@ -19,16 +19,27 @@ package main
 import (
 	"flag"
 	"os"
+	"strings"
+	"time"
+
+	// Embed the time zone database so schedules can be evaluated in any time zone.
+	_ "time/tzdata"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	"go.uber.org/zap/zapcore"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
@@ -53,11 +64,26 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var protectedNamespaces string
+	var killSwitch string
+	var minInterval time.Duration
+	var watchNamespaces string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&protectedNamespaces, "protected-namespaces", "kube-system,kube-public,kube-node-lease",
+		"Comma separated list of namespaces the controller will never delete pods in. "+
+			"The namespace the manager runs in, taken from the POD_NAMESPACE environment variable, is always protected.")
+	flag.StringVar(&killSwitch, "kill-switch-configmap", "podchaosmonkey-kill-switch",
+		"The name, or namespace/name, of the ConfigMap that suspends every Monkey while its suspend key is \"true\". "+
+			"Defaults to the namespace the manager runs in, taken from the POD_NAMESPACE environment variable.")
+	flag.DurationVar(&minInterval, "min-interval", podchaosv1alpha1.DefaultInterval,
+		"The shortest interval between Chaos experiments the validating webhook allows a Monkey to set.")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", os.Getenv("WATCH_NAMESPACES"),
+		"Comma separated list of namespaces the controller watches, defaults to the WATCH_NAMESPACES environment variable. "+
+			"The controller watches every namespace when empty and only needs namespaced Roles in the listed namespaces when set.")
 	opts := zap.Options{
 		Development: true,
 		TimeEncoder: zapcore.ISO8601TimeEncoder,
@@ -67,6 +93,26 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	killSwitchKey := parseKillSwitch(killSwitch, os.Getenv("POD_NAMESPACE"))
+	if killSwitchKey.Namespace == "" {
+		killSwitchKey.Namespace = corev1.NamespaceDefault
+		setupLog.Info("POD_NAMESPACE is not set, looking for the kill switch in the default namespace", "killSwitch", killSwitchKey)
+	}
+	// Only the kill switch ConfigMap is cached rather than every ConfigMap in the cluster
+	cacheOptions := cache.Options{
+		SelectorsByObject: cache.SelectorsByObject{
+			&corev1.ConfigMap{}: {Field: fields.SelectorFromSet(fields.Set{
+				"metadata.name":      killSwitchKey.Name,
+				"metadata.namespace": killSwitchKey.Namespace,
+			})},
+		},
+	}
+	newCache := cache.BuilderWithOptions(cacheOptions)
+	watched := parseNamespaces(watchNamespaces)
+	if len(watched) > 0 {
+		setupLog.Info("watching namespaces", "namespaces", watched)
+		newCache = namespacedCacheBuilder(watched, killSwitchKey.Namespace, cacheOptions)
+	}
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		MetricsBindAddress:     metricsAddr,
@@ -74,19 +120,37 @@ func main() {
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "ad75ffcd.podchaosmonkey.pt",
+		NewCache:               newCache,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	protected := parseNamespaces(protectedNamespaces, os.Getenv("POD_NAMESPACE"))
 	if err = (&controllers.MonkeyReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:              mgr.GetClient(),
+		Scheme:              mgr.GetScheme(),
+		Clientset:           kubernetes.NewForConfigOrDie(mgr.GetConfig()),
+		Recorder:            mgr.GetEventRecorderFor("podchaosmonkey"),
+		ProtectedNamespaces: protected,
+		WatchNamespaces:     watched,
+		KillSwitch:          killSwitchKey,
+		APIReader:           mgr.GetAPIReader(),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Monkey")
 		os.Exit(1)
 	}
+	// Webhooks need serving certificates, set ENABLE_WEBHOOKS=false to run the manager locally without them
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = (&podchaosv1alpha1.Monkey{}).SetupWebhookWithManager(mgr, &podchaosv1alpha1.MonkeyValidator{
+			MinInterval:         minInterval,
+			ProtectedNamespaces: protected,
+		}); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Monkey")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -104,3 +168,39 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// parseNamespaces splits a comma separated list of namespaces and appends any extra namespaces provided
+func parseNamespaces(list string, extra ...string) []string {
+	var namespaces []string
+	for _, namespace := range append(strings.Split(list, ","), extra...) {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
+
+// parseKillSwitch parses the name, or namespace/name, of the kill switch ConfigMap, defaulting to the namespace provided
+func parseKillSwitch(value, namespace string) types.NamespacedName {
+	if parts := strings.SplitN(value, "/", 2); len(parts) == 2 {
+		return types.NamespacedName{Namespace: parts[0], Name: parts[1]}
+	}
+	return types.NamespacedName{Namespace: namespace, Name: value}
+}
+
+// namespacedCacheBuilder restricts the cache to the namespaces provided and the namespace of the kill switch while
+// keeping the selectors of the options
+func namespacedCacheBuilder(namespaces []string, killSwitchNamespace string, options cache.Options) cache.NewCacheFunc {
+	cached := append([]string{}, namespaces...)
+	found := false
+	for _, namespace := range namespaces {
+		found = found || namespace == killSwitchNamespace
+	}
+	if !found {
+		cached = append(cached, killSwitchNamespace)
+	}
+	return func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+		opts.SelectorsByObject = options.SelectorsByObject
+		return cache.MultiNamespacedCacheBuilder(cached)(config, opts)
+	}
+}